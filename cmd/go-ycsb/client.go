@@ -60,6 +60,14 @@ func runClientCommandFunc(cmd *cobra.Command, args []string, doTransactions bool
 		if cmd.Flags().Changed("mock") {
 			globalProps.Set(prop.Mock, strconv.FormatBool(mockArg))
 		}
+
+		if cmd.Flags().Changed("resume") {
+			globalProps.Set(prop.LoadResume, strconv.FormatBool(resumeArg))
+		}
+
+		if cmd.Flags().Changed("append") {
+			globalProps.Set(prop.LoadAppend, strconv.FormatBool(appendArg))
+		}
 	})
 
 	fmt.Println("***************** properties *****************")
@@ -91,6 +99,8 @@ var (
 	silenceArg   bool
 	randomKeyArg bool
 	mockArg      bool
+	resumeArg    bool
+	appendArg    bool
 )
 
 func initClientCommand(m *cobra.Command) {
@@ -114,6 +124,8 @@ func newLoadCommand() *cobra.Command {
 	}
 
 	initClientCommand(m)
+	m.Flags().BoolVar(&resumeArg, "resume", false, "Resume the load from the checkpoint - can also be specified as the \"load.resume\" property")
+	m.Flags().BoolVar(&appendArg, "append", false, "Insert the records after the loaded ones - can also be specified as the \"load.append\" property")
 	return m
 }
 
@@ -51,6 +51,8 @@ import (
 	_ "github.com/pingcap/go-ycsb/db/badger"
 	// Register RocksDB database
 	_ "github.com/pingcap/go-ycsb/db/rocksdb"
+	// Register Pebble database
+	_ "github.com/pingcap/go-ycsb/db/pebble"
 	// Register Spanner database
 	_ "github.com/pingcap/go-ycsb/db/spanner"
 	// Register pegasus database
@@ -65,6 +67,14 @@ import (
 	_ "github.com/pingcap/go-ycsb/db/redis"
 	// Register boltdb database
 	_ "github.com/pingcap/go-ycsb/db/boltdb"
+	// Register leveldb database
+	_ "github.com/pingcap/go-ycsb/db/leveldb"
+	// Register etcd database
+	_ "github.com/pingcap/go-ycsb/db/etcd"
+	// Register memcached database
+	_ "github.com/pingcap/go-ycsb/db/memcached"
+	// Register memory database
+	_ "github.com/pingcap/go-ycsb/db/memory"
 
 	_ "github.com/pingcap/go-ycsb/db/rados"
 
@@ -130,7 +140,7 @@ func initialGlobal(dbName string, onProperties func()) {
 	if globalDB, err = dbCreator.Create(globalProps); err != nil {
 		util.Fatalf("create db %s failed %v", dbName, err)
 	}
-	globalDB = client.DbWrapper{globalDB}
+	globalDB = client.DbWrapper{client.NewFaultyDB(globalProps, globalDB)}
 }
 
 func main() {
@@ -171,6 +181,8 @@ func main() {
 		newShellCommand(),
 		newLoadCommand(),
 		newRunCommand(),
+		newScenarioCommand(),
+		newAnalyzeTraceCommand(),
 	)
 
 	cobra.EnablePrefixMatching = true
@@ -0,0 +1,201 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/client"
+	"github.com/pingcap/go-ycsb/pkg/measurement"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+	"github.com/spf13/cobra"
+)
+
+// A scenario file is a property file which describes ordered phases, like:
+//
+//	recordcount=100000
+//	phases=load,a,c
+//	phase.load.dotransactions=false
+//	phase.a.propertyfile=workloads/workloada
+//	phase.a.threadcount=32
+//	phase.a.maxexecutiontime=300
+//	phase.c.propertyfile=workloads/workloadc
+//	phase.c.target=10000
+//
+// The properties of a phase are loaded from its property files first, then the
+// shared properties in the scenario, and at last the phase.<name>.* overrides.
+const (
+	scenarioPhases       = "phases"
+	scenarioPhasePrefix  = "phase."
+	scenarioPropertyFile = "propertyfile"
+)
+
+// scenarioDBKeys are the properties read when the DB is created. The DB is shared by the
+// phases and created with the shared properties, so a phase can't change them, nor the
+// <db>.* and fault.* properties.
+var scenarioDBKeys = map[string]struct{}{
+	prop.TableName:   {},
+	prop.FieldCount:  {},
+	prop.FieldLength: {},
+	prop.Schema:      {},
+	prop.DropData:    {},
+}
+
+type scenarioPhase struct {
+	name string
+	p    *properties.Properties
+}
+
+func isScenarioDBKey(dbName string, key string) bool {
+	_, ok := scenarioDBKeys[key]
+	return ok || strings.HasPrefix(key, dbName+".") || strings.HasPrefix(key, "fault.")
+}
+
+// loadScenario returns the phases of the scenario for the db. It fails if a phase sets a
+// DB property to a value other than the shared one.
+func loadScenario(dbName string, shared *properties.Properties) ([]scenarioPhase, error) {
+	names := strings.Split(shared.GetString(scenarioPhases, ""), ",")
+	base := shared.FilterFunc(func(k, v string) bool {
+		return k != scenarioPhases && !strings.HasPrefix(k, scenarioPhasePrefix)
+	})
+
+	phases := make([]scenarioPhase, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+
+		overrides := shared.FilterStripPrefix(scenarioPhasePrefix + name + ".")
+		p := properties.NewProperties()
+		if files := overrides.GetString(scenarioPropertyFile, ""); len(files) > 0 {
+			var err error
+			if p, err = properties.LoadFiles(strings.Split(files, ","), properties.UTF8, false); err != nil {
+				return nil, fmt.Errorf("load the property files of phase %s failed %v", name, err)
+			}
+			overrides.Delete(scenarioPropertyFile)
+		}
+		p.Merge(base)
+		p.Merge(overrides)
+
+		for _, key := range p.Keys() {
+			if !isScenarioDBKey(dbName, key) {
+				continue
+			}
+			if v, ok := base.Get(key); !ok || v != p.GetString(key, "") {
+				return nil, fmt.Errorf("phase %s sets %s of the shared db, which must be set in the shared properties", name, key)
+			}
+		}
+
+		phases = append(phases, scenarioPhase{name: name, p: p})
+	}
+
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("no phase is defined in the scenario, please set %s", scenarioPhases)
+	}
+	return phases, nil
+}
+
+func runScenarioPhase(phase scenarioPhase) {
+	measurement.InitMeasure(phase.p)
+
+	workloadName := phase.p.GetString(prop.Workload, "core")
+	workloadCreator := ycsb.GetWorkloadCreator(workloadName)
+
+	var err error
+	if globalWorkload, err = workloadCreator.Create(phase.p); err != nil {
+		util.Fatalf("create workload %s for phase %s failed %v", workloadName, phase.name, err)
+	}
+
+	fmt.Printf("***************** phase %s *****************\n", phase.name)
+	for key, value := range phase.p.Map() {
+		fmt.Printf("\"%s\"=\"%s\"\n", key, value)
+	}
+	fmt.Println("**********************************************")
+
+	c := client.NewClient(phase.p, globalWorkload, globalDB)
+	start := time.Now()
+	c.Run(globalContext)
+
+	fmt.Printf("Phase %s finished, takes %s\n", phase.name, time.Now().Sub(start))
+	measurement.Output()
+
+	globalWorkload.Close()
+	globalWorkload = nil
+}
+
+func runScenarioCommandFunc(cmd *cobra.Command, args []string) {
+	dbName := args[0]
+
+	globalProps = properties.MustLoadFile(args[1], properties.UTF8)
+	for _, prop := range propertyValues {
+		seps := strings.SplitN(prop, "=", 2)
+		globalProps.Set(seps[0], seps[1])
+	}
+	if len(tableName) > 0 {
+		globalProps.Set(prop.TableName, tableName)
+	}
+
+	phases, err := loadScenario(dbName, globalProps)
+	if err != nil {
+		util.Fatalf("load scenario %s failed %v", args[1], err)
+	}
+
+	addr := globalProps.GetString(prop.DebugPprof, prop.DebugPprofDefault)
+	go func() {
+		http.ListenAndServe(addr, nil)
+	}()
+
+	// The DB is shared by all the phases, so it is created with the shared properties.
+	measurement.InitMeasure(globalProps)
+	dbCreator := ycsb.GetDBCreator(dbName)
+	if dbCreator == nil {
+		util.Fatalf("%s is not registered", dbName)
+	}
+	db, err := dbCreator.Create(globalProps)
+	if err != nil {
+		util.Fatalf("create db %s failed %v", dbName, err)
+	}
+	globalDB = client.DbWrapper{DB: client.NewFaultyDB(globalProps, db)}
+
+	start := time.Now()
+	for _, phase := range phases {
+		select {
+		case <-globalContext.Done():
+			return
+		default:
+		}
+		runScenarioPhase(phase)
+	}
+	fmt.Printf("Scenario finished, takes %s\n", time.Now().Sub(start))
+}
+
+func newScenarioCommand() *cobra.Command {
+	m := &cobra.Command{
+		Use:   "scenario db file",
+		Short: "YCSB multi-phase benchmark",
+		Args:  cobra.ExactArgs(2),
+		Run:   runScenarioCommandFunc,
+	}
+
+	m.Flags().StringArrayVarP(&propertyValues, "prop", "p", nil, "Specify a shared property value with name=value")
+	m.Flags().StringVar(&tableName, "table", "", "Use the table name instead of the default \""+prop.TableNameDefault+"\"")
+	return m
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties"
+)
+
+func writeScenarioFile(t *testing.T, dir string, name string, s string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(s), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	workload := writeScenarioFile(t, dir, "workload", "recordcount=10\nreadproportion=0.5\nfieldcount=10\n")
+	shared := properties.MustLoadString(`
+recordcount=100
+fieldcount=10
+phases=load, run
+phase.load.dotransactions=false
+phase.load.propertyfile=` + workload + `
+phase.run.propertyfile=` + workload + `
+phase.run.readproportion=1
+`)
+
+	phases, err := loadScenario("basic", shared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(phases) != 2 || phases[0].name != "load" || phases[1].name != "run" {
+		t.Fatalf("want the phases load and run, but got %v", phases)
+	}
+
+	// The shared properties override the files, and the phase properties override both.
+	for i, want := range []map[string]string{
+		{"recordcount": "100", "readproportion": "0.5", "dotransactions": "false"},
+		{"recordcount": "100", "readproportion": "1", "dotransactions": ""},
+	} {
+		p := phases[i].p
+		for key, value := range want {
+			if got := p.GetString(key, ""); got != value {
+				t.Fatalf("want %s=%q in phase %s, but got %q", key, value, phases[i].name, got)
+			}
+		}
+		for _, key := range []string{scenarioPhases, scenarioPropertyFile, "phase.run.readproportion"} {
+			if _, ok := p.Get(key); ok {
+				t.Fatalf("want no %s in phase %s", key, phases[i].name)
+			}
+		}
+	}
+}
+
+func TestLoadScenarioDBKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scenario")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	workload := writeScenarioFile(t, dir, "workload", "table=othertable\n")
+	for _, s := range []string{
+		"phase.a.fieldcount=20\n",
+		"phase.a.basic.ordered=true\n",
+		"phase.a.fault.errorrate=0.1\n",
+		"phase.a.propertyfile=" + workload + "\n",
+		"phase.a.propertyfile=" + filepath.Join(dir, "missing") + "\n",
+		"phases=\n",
+	} {
+		if _, err := loadScenario("basic", properties.MustLoadString("phases=a\n"+s)); err == nil {
+			t.Fatalf("want an error for the scenario %q", s)
+		}
+	}
+
+	// A phase may repeat the shared value of a DB property.
+	phases, err := loadScenario("basic", properties.MustLoadString(`
+phases=a
+table=othertable
+basic.verbose=true
+phase.a.propertyfile=`+workload+`
+phase.a.basic.verbose=true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table := phases[0].p.GetString("table", ""); table != "othertable" {
+		t.Fatalf("want the table othertable, but got %s", table)
+	}
+	if _, err = loadScenario("memory", properties.MustLoadString("phases=a\nphase.a.basic.ordered=true\n")); err != nil {
+		t.Fatalf("want no error for the properties of another db, but got %v", err)
+	}
+	if _, err = loadScenario("basic", properties.MustLoadString("phases=a\nphase.a.fieldcount=10\n")); err == nil || !strings.Contains(err.Error(), "fieldcount") {
+		t.Fatalf("want an error about fieldcount, but got %v", err)
+	}
+}
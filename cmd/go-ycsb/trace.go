@@ -0,0 +1,123 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	traceOutput string
+	traceColumn int
+)
+
+// analyzeTrace counts the accesses of every key in the op log, and returns the counts
+// from the hottest key to the coldest one.
+func analyzeTrace(name string, column int) ([]int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	counts := make(map[string]int64)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		i := column
+		if i < 0 {
+			i += len(fields)
+		}
+		if i < 0 || i >= len(fields) {
+			continue
+		}
+		counts[fields[i]]++
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	freqs := make([]int64, 0, len(counts))
+	for _, c := range counts {
+		freqs = append(freqs, c)
+	}
+	sort.Slice(freqs, func(i, j int) bool { return freqs[i] > freqs[j] })
+	return freqs, nil
+}
+
+func runAnalyzeTraceCommandFunc(cmd *cobra.Command, args []string) {
+	freqs, err := analyzeTrace(args[0], traceColumn)
+	if err != nil {
+		util.Fatalf("analyze op log %s failed %v", args[0], err)
+	}
+	if len(freqs) == 0 {
+		util.Fatalf("no key is found in op log %s", args[0])
+	}
+
+	f, err := os.Create(traceOutput)
+	if err != nil {
+		util.Fatalf("create %s failed %v", traceOutput, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	var total int64
+	for rank, freq := range freqs {
+		fmt.Fprintf(w, "%d\t%d\n", rank, freq)
+		total += freq
+	}
+	if err = w.Flush(); err != nil {
+		util.Fatalf("write %s failed %v", traceOutput, err)
+	}
+
+	fmt.Printf("Analyzed %d operations on %d keys, written to %s\n", total, len(freqs), traceOutput)
+	for _, top := range []float64{0.01, 0.1, 0.2} {
+		n := int(float64(len(freqs)) * top)
+		if n == 0 {
+			continue
+		}
+		var sum int64
+		for _, freq := range freqs[:n] {
+			sum += freq
+		}
+		fmt.Printf("The hottest %.0f%% keys take %.2f%% of the operations\n", top*100, float64(sum)*100/float64(total))
+	}
+}
+
+func newAnalyzeTraceCommand() *cobra.Command {
+	m := &cobra.Command{
+		Use:   "analyze-trace oplog",
+		Short: "Generate the key frequency file of the empirical distribution from an op log",
+		Long: "Every line of the op log is an operation with whitespace-separated columns, and one of them is the key.\n" +
+			"The output file is used by requestdistribution=empirical with the empirical.file property.",
+		Args: cobra.ExactArgs(1),
+		Run:  runAnalyzeTraceCommandFunc,
+	}
+
+	m.Flags().StringVarP(&traceOutput, "output", "o", "keyfreq.txt", "The key frequency file to write")
+	m.Flags().IntVar(&traceColumn, "column", -1, "The column of the key, counted from 0, and negative counts from the end")
+	return m
+}
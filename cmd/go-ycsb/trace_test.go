@@ -0,0 +1,97 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pingcap/go-ycsb/pkg/generator"
+)
+
+const testTrace = `# time op key
+1 READ k1
+2 UPDATE k2 extra
+3 READ k1
+
+4 READ k3
+5 INSERT k1
+6 READ
+`
+
+func TestAnalyzeTrace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "oplog")
+	if err = ioutil.WriteFile(name, []byte(testTrace), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for column, want := range map[int][]int64{
+		// The key is the third column, and the line without it is skipped.
+		2: {3, 1, 1},
+		// The last column is "extra" for the UPDATE and "READ" for the last line.
+		-1: {3, 1, 1, 1},
+		0:  {1, 1, 1, 1, 1, 1},
+		5:  {},
+	} {
+		freqs, err := analyzeTrace(name, column)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(freqs, want) {
+			t.Fatalf("want %v of the column %d, but got %v", want, column, freqs)
+		}
+	}
+
+	if _, err = analyzeTrace(filepath.Join(dir, "missing"), 2); err == nil {
+		t.Fatal("want an error for the missing op log")
+	}
+}
+
+func TestAnalyzeTraceCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "oplog")
+	if err = ioutil.WriteFile(name, []byte(testTrace), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newAnalyzeTraceCommand()
+	output := filepath.Join(dir, "keyfreq.txt")
+	cmd.SetArgs([]string{name, "--column", "2", "-o", output})
+	if err = cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The output is the frequency file of the empirical distribution.
+	freqs, err := generator.LoadFrequencyFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{3, 1, 1}; !reflect.DeepEqual(freqs, want) {
+		t.Fatalf("want %v, but got %v", want, freqs)
+	}
+}
@@ -2,22 +2,78 @@ package aerospike
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	as "github.com/aerospike/aerospike-client-go"
+	ast "github.com/aerospike/aerospike-client-go/types"
 	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
 	"github.com/pingcap/go-ycsb/pkg/ycsb"
 )
 
 const (
-	asNs   = "aerospike.ns"
-	asHost = "aerospike.host"
-	asPort = "aerospike.port"
+	asNs            = "aerospike.ns"
+	asHost          = "aerospike.host"
+	asPort          = "aerospike.port"
+	asCommitLevel   = "aerospike.commit_level"
+	asTTL           = "aerospike.ttl"
+	asDurableDelete = "aerospike.durable_delete"
+	asOrderPrefix   = "aerospike.order_prefix"
+)
+
+// orderBin keeps the first orderLen bytes of the key after the order prefix as a number,
+// which has a secondary index, so Scan can query the records in a range of the keys.
+const (
+	orderBin = "ycsb_order"
+	orderLen = 7
+	maxOrder = int64(1<<(8*orderLen) - 1)
 )
 
 type aerospikedb struct {
-	client *as.Client
-	ns     string
+	client    *as.Client
+	ns        string
+	keyPrefix string
+
+	writePolicy  *as.WritePolicy
+	updatePolicy *as.WritePolicy
+}
+
+// keyOrder returns the order of the key, and the keys with smaller orders are smaller.
+// The prefix shared by the keys is skipped, so the order has the bytes that differ. The
+// keys without the prefix are smaller or larger than all the keys with it, and get the
+// smallest or the largest order.
+func keyOrder(prefix string, key string) int64 {
+	if !strings.HasPrefix(key, prefix) {
+		if key < prefix {
+			return 0
+		}
+		return maxOrder
+	}
+
+	var b [8]byte
+	copy(b[8-orderLen:], key[len(prefix):])
+	return int64(binary.BigEndian.Uint64(b[:]))
+}
+
+// toRow converts the bins to a row, without the order bin.
+func toRow(bins as.BinMap) (map[string][]byte, error) {
+	res := make(map[string][]byte, len(bins))
+	for k, v := range bins {
+		if k == orderBin {
+			continue
+		}
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, errors.New("couldn't convert to byte array")
+		}
+		res[k] = b
+	}
+	return res, nil
 }
 
 // Close closes the database layer.
@@ -43,65 +99,136 @@ func (adb *aerospikedb) CleanupThread(ctx context.Context) {
 // fileds: The list of fields to read, nil|empty for reading all.
 func (adb *aerospikedb) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
 	asKey, err := as.NewKey(adb.ns, table, key)
-	record, err := adb.client.Get(nil, asKey)
+	if err != nil {
+		return nil, err
+	}
+	record, err := adb.client.Get(nil, asKey, fields...)
 	if err != nil {
 		return nil, err
 	}
 	if record == nil {
 		return map[string][]byte{}, nil
 	}
-	res := make(map[string][]byte, len(record.Bins))
-	var ok bool
-	for k, v := range record.Bins {
-		res[k], ok = v.([]byte)
-		if !ok {
-			return nil, errors.New("couldn't convert to byte array")
+	return toRow(record.Bins)
+}
+
+// BatchRead reads multiple records from the database in one batch request.
+// table: The name of the table.
+// keys: The keys of the records to read.
+// fields: The list of fields to read, nil|empty for reading all.
+func (adb *aerospikedb) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	asKeys := make([]*as.Key, 0, len(keys))
+	for _, key := range keys {
+		asKey, err := as.NewKey(adb.ns, table, key)
+		if err != nil {
+			return nil, err
 		}
+		asKeys = append(asKeys, asKey)
+	}
+
+	records, err := adb.client.BatchGet(nil, asKeys, fields...)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]map[string][]byte, 0, len(records))
+	for _, record := range records {
+		// Like Get, a missing record is an error.
+		if record == nil {
+			return nil, ast.ErrKeyNotFound
+		}
+		row, err := toRow(record.Bins)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, row)
 	}
 	return res, nil
 }
 
-// Scan scans records from the database.
+// Scan scans records from the database in the key order. The records are queried by the
+// secondary index of the order bin, and sorted by the keys.
 // table: The name of the table.
 // startKey: The first record key to read.
 // count: The number of records to read.
 // fields: The list of fields to read, nil|empty for reading all.
 func (adb *aerospikedb) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
-	policy := as.NewScanPolicy()
-	policy.ConcurrentNodes = true
-	recordset, err := adb.client.ScanAll(policy, adb.ns, table)
+	records, err := scanRecords(keyOrder(adb.keyPrefix, startKey), startKey, count, func(start int64, end int64) ([]*as.Record, error) {
+		return adb.queryOrders(table, fields, start, end)
+	})
 	if err != nil {
 		return nil, err
 	}
-	filter := make(map[string]bool, len(fields))
-	for _, field := range fields {
-		filter[field] = true
+
+	scanRes := make([]map[string][]byte, 0, len(records))
+	for _, record := range records {
+		row, err := toRow(record.Bins)
+		if err != nil {
+			return nil, err
+		}
+		scanRes = append(scanRes, row)
+	}
+	return scanRes, nil
+}
+
+// queryOrders queries the records whose orders are in [start, end].
+func (adb *aerospikedb) queryOrders(table string, fields []string, start int64, end int64) ([]*as.Record, error) {
+	stmt := as.NewStatement(adb.ns, table, fields...)
+	if err := stmt.SetFilter(as.NewRangeFilter(orderBin, start, end)); err != nil {
+		return nil, err
+	}
+	recordset, err := adb.client.Query(nil, stmt)
+	if err != nil {
+		return nil, err
 	}
-	scanRes := make([]map[string][]byte, 0)
-	var ok bool
-	nRead := 0
+	defer recordset.Close()
+
+	var records []*as.Record
 	for res := range recordset.Results() {
 		if res.Err != nil {
-			recordset.Close()
 			return nil, res.Err
 		}
-		vals := make(map[string][]byte, len(res.Record.Bins))
-		for k, v := range res.Record.Bins {
-			if !filter[k] {
-				continue
-			}
-			vals[k], ok = v.([]byte)
-			if !ok {
-				return nil, errors.New("couldn't convert to byte array")
+		if _, ok := res.Record.Key.Value().GetObject().(string); !ok {
+			return nil, fmt.Errorf("the key of the record %v isn't stored", res.Record.Key)
+		}
+		records = append(records, res.Record)
+	}
+	return records, nil
+}
+
+// scanRecords queries the growing ranges of the orders from start, until the ranges have
+// count records from startKey. All the records in a range are read, so the records from
+// startKey are sorted by the keys and the first count records are returned.
+func scanRecords(start int64, startKey string, count int, query func(start int64, end int64) ([]*as.Record, error)) ([]*as.Record, error) {
+	var records []*as.Record
+	for width := int64(1); ; width *= 16 {
+		end := maxOrder
+		if width <= maxOrder-start {
+			end = start + width - 1
+		}
+
+		res, err := query(start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range res {
+			if record.Key.Value().GetObject().(string) >= startKey {
+				records = append(records, record)
 			}
 		}
-		scanRes = append(scanRes, vals)
-		nRead++
-		if nRead == count {
+
+		if len(records) >= count || end == maxOrder {
 			break
 		}
+		start = end + 1
 	}
-	return scanRes, nil
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Key.Value().GetObject().(string) < records[j].Key.Value().GetObject().(string)
+	})
+	if len(records) > count {
+		records = records[:count]
+	}
+	return records, nil
 }
 
 // Update updates a record in the database. Any field/value pairs will be written into the
@@ -114,21 +241,26 @@ func (adb *aerospikedb) Update(ctx context.Context, table string, key string, va
 	if err != nil {
 		return err
 	}
-	record, err := adb.client.Get(nil, asKey)
-	if err != nil {
-		return err
-	}
-	bins := as.BinMap{}
-	var policy *as.WritePolicy
-	if record != nil {
-		bins = record.Bins
-		policy := as.NewWritePolicy(record.Generation, 0)
-		policy.GenerationPolicy = as.EXPECT_GEN_EQUAL
-	}
+	// Only the bins of the values are written, and the other bins are kept.
+	bins := make(as.BinMap, len(values))
 	for k, v := range values {
 		bins[k] = v
 	}
-	return adb.client.Put(policy, asKey, bins)
+	return adb.client.Put(adb.updatePolicy, asKey, bins)
+}
+
+// BatchUpdate updates multiple records in the database. The client doesn't have batch
+// writes, so the records are updated one by one.
+// table: The name of the table.
+// keys: The keys of the records to update.
+// values: The maps of field/value pairs to update in the records.
+func (adb *aerospikedb) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	for i, key := range keys {
+		if err := adb.Update(ctx, table, key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Insert inserts a record in the database. Any field/value pairs will be written into the
@@ -141,13 +273,25 @@ func (adb *aerospikedb) Insert(ctx context.Context, table string, key string, va
 	if err != nil {
 		return err
 	}
-	bins := make([]*as.Bin, len(values))
-	i := 0
+	bins := make([]*as.Bin, 0, len(values)+1)
 	for k, v := range values {
-		bins[i] = as.NewBin(k, v)
-		i++
+		bins = append(bins, as.NewBin(k, v))
+	}
+	bins = append(bins, as.NewBin(orderBin, keyOrder(adb.keyPrefix, key)))
+	return adb.client.PutBins(adb.writePolicy, asKey, bins...)
+}
+
+// BatchInsert inserts multiple records in the database one by one.
+// table: The name of the table.
+// keys: The keys of the records to insert.
+// values: The maps of field/value pairs to insert in the records.
+func (adb *aerospikedb) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	for i, key := range keys {
+		if err := adb.Insert(ctx, table, key, values[i]); err != nil {
+			return err
+		}
 	}
-	return adb.client.PutBins(nil, asKey, bins...)
+	return nil
 }
 
 // Delete deletes a record from the database.
@@ -158,18 +302,63 @@ func (adb *aerospikedb) Delete(ctx context.Context, table string, key string) er
 	if err != nil {
 		return err
 	}
-	_, err = adb.client.Delete(nil, asKey)
+	_, err = adb.client.Delete(adb.writePolicy, asKey)
 	return err
 }
 
+// BatchDelete deletes multiple records from the database one by one.
+// table: The name of the table.
+// keys: The keys of the records to delete.
+func (adb *aerospikedb) BatchDelete(ctx context.Context, table string, keys []string) error {
+	for _, key := range keys {
+		if err := adb.Delete(ctx, table, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type aerospikeCreator struct{}
 
 func (a aerospikeCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	adb := &aerospikedb{}
 	adb.ns = p.GetString(asNs, "test")
+	adb.keyPrefix = p.GetString(asOrderPrefix, "")
+
+	// The keys are stored for Scan.
+	adb.writePolicy = as.NewWritePolicy(0, uint32(p.GetParsedDuration(asTTL, 0)/time.Second))
+	adb.writePolicy.SendKey = true
+	adb.writePolicy.DurableDelete = p.GetBool(asDurableDelete, false)
+	switch level := p.GetString(asCommitLevel, "all"); level {
+	case "all":
+		adb.writePolicy.CommitLevel = as.COMMIT_ALL
+	case "master":
+		adb.writePolicy.CommitLevel = as.COMMIT_MASTER
+	default:
+		return nil, fmt.Errorf("unsupported aerospike commit level %s", level)
+	}
+	updatePolicy := *adb.writePolicy
+	updatePolicy.RecordExistsAction = as.UPDATE_ONLY
+	adb.updatePolicy = &updatePolicy
+
 	var err error
 	adb.client, err = as.NewClient(p.GetString(asHost, "localhost"), p.GetInt(asPort, 3000))
-	return adb, err
+	if err != nil {
+		return nil, err
+	}
+
+	table := p.GetString(prop.TableName, prop.TableNameDefault)
+	task, err := adb.client.CreateIndex(nil, adb.ns, table, table+"_"+orderBin, orderBin, as.NUMERIC)
+	if err != nil {
+		if aerr, ok := err.(ast.AerospikeError); !ok || aerr.ResultCode() != ast.INDEX_FOUND {
+			adb.client.Close()
+			return nil, err
+		}
+	} else if err = <-task.OnComplete(); err != nil {
+		adb.client.Close()
+		return nil, err
+	}
+	return adb, nil
 }
 
 func init() {
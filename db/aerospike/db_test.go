@@ -0,0 +1,149 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go"
+	"github.com/pingcap/go-ycsb/pkg/util"
+)
+
+func checkOrders(t *testing.T, prefix string, keys []string) {
+	t.Helper()
+	if !sort.StringsAreSorted(keys) {
+		t.Fatal("the keys must be sorted")
+	}
+
+	for i := 1; i < len(keys); i++ {
+		prev, cur := keyOrder(prefix, keys[i-1]), keyOrder(prefix, keys[i])
+		if prev > cur {
+			t.Fatalf("want the order of %s not larger than %s, but got %d > %d", keys[i-1], keys[i], prev, cur)
+		}
+		if cur < 0 || cur > maxOrder {
+			t.Fatalf("the order %d of %s is out of range", cur, keys[i])
+		}
+	}
+}
+
+func TestKeyOrder(t *testing.T) {
+	checkOrders(t, "", []string{"", "a", "user", "user1", "user10", "user12345678", "user12345679", "user2", "zzzzzzzzzz"})
+
+	// The keys with the same prefix have the same order.
+	if keyOrder("", "user12345678") != keyOrder("", "user12345679") {
+		t.Fatal("want the same order of the keys with the same prefix")
+	}
+
+	// The keys without the key prefix are out of the orders of the keys with it.
+	checkOrders(t, "host_user", []string{"a", "host_use", "host_user", "host_user0", "host_user999", "host_userzzzzzzzzzz", "host_usf", "z"})
+}
+
+func hashedKeys(prefix string, n int) []string {
+	keys := make([]string, 0, n)
+	for i := int64(0); i < int64(n); i++ {
+		keys = append(keys, fmt.Sprintf("%s%d", prefix, util.Hash64(i)))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestHashedKeyOrder(t *testing.T) {
+	// The default keys are the hashed numbers after the hostname and the keyprefix.
+	prefix := "host_user"
+	keys := hashedKeys(prefix, 1000)
+	checkOrders(t, prefix, keys)
+
+	orders := make(map[int64]struct{}, len(keys))
+	for _, key := range keys {
+		orders[keyOrder(prefix, key)] = struct{}{}
+	}
+	if len(orders) < len(keys)*9/10 {
+		t.Fatalf("want the keys in different orders, but got %d orders of %d keys", len(orders), len(keys))
+	}
+}
+
+func TestScanRecords(t *testing.T) {
+	prefix := "host_user"
+	keys := append(hashedKeys(prefix, 1000), "a", "z")
+	sort.Strings(keys)
+	records := make([]*as.Record, 0, len(keys))
+	for _, key := range keys {
+		asKey, err := as.NewKey("test", "usertable", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, &as.Record{Key: asKey})
+	}
+
+	// query returns the records in the range in the reversed order.
+	queries := 0
+	query := func(start int64, end int64) ([]*as.Record, error) {
+		queries++
+		if start > end {
+			t.Fatalf("invalid range [%d, %d]", start, end)
+		}
+		var res []*as.Record
+		for i := len(records) - 1; i >= 0; i-- {
+			order := keyOrder(prefix, keys[i])
+			if order >= start && order <= end {
+				res = append(res, records[i])
+			}
+		}
+		return res, nil
+	}
+
+	for _, c := range []struct {
+		startKey string
+		count    int
+	}{
+		{"", 10},
+		{keys[1], 1},
+		{keys[500], 100},
+		{keys[500] + "0", 10},
+		{keys[len(keys)-10], 100},
+		{"z", 10},
+		{"zz", 10},
+	} {
+		queries = 0
+		res, err := scanRecords(keyOrder(prefix, c.startKey), c.startKey, c.count, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := keys[sort.SearchStrings(keys, c.startKey):]
+		if len(want) > c.count {
+			want = want[:c.count]
+		}
+		if len(res) != len(want) {
+			t.Fatalf("scan %d records from %s, want %d records, but got %d", c.count, c.startKey, len(want), len(res))
+		}
+		for i, record := range res {
+			if key := record.Key.Value().GetObject().(string); key != want[i] {
+				t.Fatalf("scan %d records from %s, want %s at %d, but got %s", c.count, c.startKey, want[i], i, key)
+			}
+		}
+		if queries > 15 {
+			t.Fatalf("scan %d records from %s, want at most 15 queries, but got %d", c.count, c.startKey, queries)
+		}
+	}
+
+	if _, err := scanRecords(0, "", 10, func(int64, int64) ([]*as.Record, error) {
+		return nil, errors.New("query failed")
+	}); err == nil {
+		t.Fatal("want the query error")
+	}
+}
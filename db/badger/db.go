@@ -14,6 +14,7 @@
 package badger
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -166,15 +167,26 @@ func (db *badgerDB) Read(ctx context.Context, table string, key string, fields [
 }
 
 func (db *badgerDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
-	res := make([]map[string][]byte, count)
+	return db.scan(db.getRowKey(table, startKey), nil, count, fields)
+}
+
+func (db *badgerDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(db.getRowKey(table, startKey), db.getRowKey(table, endKey), limit, fields)
+}
+
+// scan reads at most count rows from rowStartKey, and stops before rowEndKey if it is not nil.
+func (db *badgerDB) scan(rowStartKey []byte, rowEndKey []byte, count int, fields []string) ([]map[string][]byte, error) {
+	res := make([]map[string][]byte, 0, count)
 	err := db.db.View(func(txn *badger.Txn) error {
-		rowStartKey := db.getRowKey(table, startKey)
 		it := txn.NewIterator(badger.DefaultIteratorOptions)
 		defer it.Close()
 
-		i := 0
-		for it.Seek(rowStartKey); it.Valid() && i < count; it.Next() {
+		for it.Seek(rowStartKey); it.Valid() && len(res) < count; it.Next() {
 			item := it.Item()
+			if rowEndKey != nil && bytes.Compare(item.Key(), rowEndKey) >= 0 {
+				break
+			}
+
 			value, err := item.ValueCopy(nil)
 			if err != nil {
 				return err
@@ -185,8 +197,7 @@ func (db *badgerDB) Scan(ctx context.Context, table string, startKey string, cou
 				return err
 			}
 
-			res[i] = m
-			i++
+			res = append(res, m)
 		}
 
 		return nil
@@ -254,6 +265,44 @@ func (db *badgerDB) Delete(ctx context.Context, table string, key string) error
 	return err
 }
 
+// DeleteRange deletes the keys one by one because badger has no range deletion. If the
+// range is too big for one transaction, it is deleted in several transactions.
+func (db *badgerDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	rowStartKey := db.getRowKey(table, startKey)
+	rowEndKey := db.getRowKey(table, endKey)
+
+	for done := false; !done; {
+		err := db.db.Update(func(txn *badger.Txn) error {
+			opts := badger.DefaultIteratorOptions
+			opts.PrefetchValues = false
+			it := txn.NewIterator(opts)
+			defer it.Close()
+
+			for it.Seek(rowStartKey); it.Valid(); it.Next() {
+				key := it.Item().KeyCopy(nil)
+				if bytes.Compare(key, rowEndKey) >= 0 {
+					break
+				}
+				if err := txn.Delete(key); err == badger.ErrTxnTooBig {
+					// Commit the deleted keys and continue from this key.
+					rowStartKey = key
+					return nil
+				} else if err != nil {
+					return err
+				}
+			}
+
+			done = true
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func init() {
 	ycsb.RegisterDBCreator("badger", badgerCreator{})
 }
@@ -121,12 +121,12 @@ func (db *boltDB) Read(ctx context.Context, table string, key string, fields []s
 	err := db.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(table))
 		if bucket == nil {
-			return fmt.Errorf("table not found: %s", table)
+			return fmt.Errorf("table %w: %s", ycsb.ErrNotFound, table)
 		}
 
 		row := bucket.Get([]byte(key))
 		if row == nil {
-			return fmt.Errorf("key not found: %s.%s", table, key)
+			return fmt.Errorf("key %w: %s.%s", ycsb.ErrNotFound, table, key)
 		}
 
 		var err error
@@ -16,6 +16,7 @@ package cassandra
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -30,15 +31,27 @@ import (
 
 // cassandra properties
 const (
-	cassandraCluster     = "cassandra.cluster"
-	cassandraKeyspace    = "cassandra.keyspace"
-	cassandraConnections = "cassandra.connections"
-
-	cassandraClusterDefault     = "127.0.0.1:9042"
-	cassandraKeyspaceDefault    = "test"
-	cassandraConnectionsDefault = 2 // refer to https://github.com/gocql/gocql/blob/master/cluster.go#L52
+	cassandraCluster          = "cassandra.cluster"
+	cassandraKeyspace         = "cassandra.keyspace"
+	cassandraConnections      = "cassandra.connections"
+	cassandraReadConsistency  = "cassandra.readconsistency"
+	cassandraWriteConsistency = "cassandra.writeconsistency"
+	cassandraBatch            = "cassandra.batch"
+	cassandraLWT              = "cassandra.lwt"
+	cassandraMaxPreparedStmts = "cassandra.max_prepared_stmts"
+
+	cassandraClusterDefault          = "127.0.0.1:9042"
+	cassandraKeyspaceDefault         = "test"
+	cassandraConnectionsDefault      = 2 // refer to https://github.com/gocql/gocql/blob/master/cluster.go#L52
+	cassandraConsistencyDefault      = "QUORUM"
+	cassandraBatchDefault            = "unlogged"
+	cassandraMaxPreparedStmtsDefault = 1000
 )
 
+// ErrNotApplied is returned by the lightweight transactions if their conditions are not met,
+// which means the inserted key exists, or the updated or deleted key doesn't exist.
+var ErrNotApplied = errors.New("cassandra: lightweight transaction is not applied")
+
 type cassandraCreator struct {
 }
 
@@ -47,10 +60,14 @@ type cassandraDB struct {
 	session *gocql.Session
 	verbose bool
 
-	bufPool  *util.BufPool
 	keySpace string
 
 	fieldNames []string
+
+	readConsistency  gocql.Consistency
+	writeConsistency gocql.Consistency
+	batchType        gocql.BatchType
+	lwt              bool
 }
 
 type contextKey string
@@ -73,6 +90,25 @@ func (c cassandraCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	cluster.NumConns = p.GetInt(cassandraConnections, cassandraConnectionsDefault)
 	cluster.Timeout = 30 * time.Second
 	cluster.Consistency = gocql.Quorum
+	cluster.MaxPreparedStmts = p.GetInt(cassandraMaxPreparedStmts, cassandraMaxPreparedStmtsDefault)
+
+	var err error
+	if d.readConsistency, err = gocql.ParseConsistencyWrapper(p.GetString(cassandraReadConsistency, cassandraConsistencyDefault)); err != nil {
+		return nil, err
+	}
+	if d.writeConsistency, err = gocql.ParseConsistencyWrapper(p.GetString(cassandraWriteConsistency, cassandraConsistencyDefault)); err != nil {
+		return nil, err
+	}
+
+	switch batch := p.GetString(cassandraBatch, cassandraBatchDefault); batch {
+	case "unlogged":
+		d.batchType = gocql.UnloggedBatch
+	case "logged":
+		d.batchType = gocql.LoggedBatch
+	default:
+		return nil, fmt.Errorf("unsupported cassandra batch type %s", batch)
+	}
+	d.lwt = p.GetBool(cassandraLWT, false)
 
 	session, err := cluster.CreateSession()
 	if err != nil {
@@ -82,8 +118,6 @@ func (c cassandraCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	d.verbose = p.GetBool(prop.Verbose, prop.VerboseDefault)
 	d.session = session
 
-	d.bufPool = util.NewBufPool()
-
 	if err := d.createTable(); err != nil {
 		return nil, err
 	}
@@ -100,19 +134,20 @@ func (db *cassandraDB) createTable() error {
 		}
 	}
 
-	fieldCount := db.p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
-
-	db.fieldNames = make([]string, fieldCount)
-	for i := int64(0); i < fieldCount; i++ {
-		db.fieldNames[i] = fmt.Sprintf("field%d", i)
-	}
+	schema := util.LoadSchema(db.p)
+	db.fieldNames = schema.FieldNames()
 
 	buf := new(bytes.Buffer)
 	s := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (YCSB_KEY VARCHAR PRIMARY KEY", db.keySpace, tableName)
 	buf.WriteString(s)
 
-	for i := int64(0); i < fieldCount; i++ {
-		buf.WriteString(fmt.Sprintf(", FIELD%d VARCHAR", i))
+	// The int and timestamp values are stored as text, so they can be scanned into bytes.
+	for _, f := range schema.Fields {
+		if f.Type == util.FieldTypeBytes {
+			buf.WriteString(fmt.Sprintf(", %s BLOB", f.Name))
+		} else {
+			buf.WriteString(fmt.Sprintf(", %s VARCHAR", f.Name))
+		}
 	}
 
 	buf.WriteString(");")
@@ -121,8 +156,32 @@ func (db *cassandraDB) createTable() error {
 		fmt.Println(buf.String())
 	}
 
-	err := db.session.Query(buf.String()).Exec()
-	return err
+	if err := db.session.Query(buf.String()).Exec(); err != nil {
+		return err
+	}
+	return db.createMetadataTable(schema)
+}
+
+// createMetadataTable creates the table of the load metadata, whose field keeps the
+// metadata in JSON.
+func (db *cassandraDB) createMetadataTable(schema *util.Schema) error {
+	tableName := util.MetadataTable(db.p)
+	f := schema.MetadataField()
+	if len(tableName) == 0 || f == nil {
+		return nil
+	}
+
+	if db.p.GetBool(prop.DropData, prop.DropDataDefault) {
+		if err := db.session.Query(fmt.Sprintf("DROP TABLE IF EXISTS %s.%s", db.keySpace, tableName)).Exec(); err != nil {
+			return err
+		}
+	}
+
+	colType := "VARCHAR"
+	if f.Type == util.FieldTypeBytes {
+		colType = "BLOB"
+	}
+	return db.session.Query(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (YCSB_KEY VARCHAR PRIMARY KEY, %s %s);", db.keySpace, tableName, f.Name, colType)).Exec()
 }
 
 func (db *cassandraDB) Close() error {
@@ -143,12 +202,11 @@ func (db *cassandraDB) CleanupThread(_ctx context.Context) {
 }
 
 func (db *cassandraDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
-	var query string
 	if len(fields) == 0 {
 		fields = db.fieldNames
 	}
 
-	query = fmt.Sprintf(`SELECT %s FROM %s.%s WHERE YCSB_KEY = ?`, strings.Join(fields, ","), db.keySpace, table)
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE YCSB_KEY = ?`, strings.Join(fields, ","), db.keySpace, table)
 
 	if db.verbose {
 		fmt.Printf("%s\n", query)
@@ -161,7 +219,7 @@ func (db *cassandraDB) Read(ctx context.Context, table string, key string, field
 		dest[i] = v
 	}
 
-	err := db.session.Query(query, key).WithContext(ctx).Scan(dest...)
+	err := db.session.Query(query, key).WithContext(ctx).Consistency(db.readConsistency).Scan(dest...)
 	if err == gocql.ErrNotFound {
 		return nil, nil
 	} else if err != nil {
@@ -175,8 +233,40 @@ func (db *cassandraDB) Read(ctx context.Context, table string, key string, field
 	return m, nil
 }
 
+// BatchRead reads the keys by an IN query. Like Read, the rows of the missing keys are nil.
+func (db *cassandraDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	if len(fields) == 0 {
+		fields = db.fieldNames
+	}
+
+	query := fmt.Sprintf(`SELECT YCSB_KEY, %s FROM %s.%s WHERE YCSB_KEY IN ?`, strings.Join(fields, ","), db.keySpace, table)
+	rows, err := db.queryRows(ctx, query, append([]string{"YCSB_KEY"}, fields...), keys)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]map[string][]byte, len(rows))
+	for _, row := range rows {
+		byKey[string(row["YCSB_KEY"])] = row
+		delete(row, "YCSB_KEY")
+	}
+
+	res := make([]map[string][]byte, len(keys))
+	for i, key := range keys {
+		res[i] = byKey[key]
+	}
+	return res, nil
+}
+
+// Scan reads the rows in the token order from the token of the start key, which is the
+// key order only with the ByteOrderedPartitioner.
 func (db *cassandraDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
-	return nil, fmt.Errorf("scan is not supported")
+	if len(fields) == 0 {
+		fields = db.fieldNames
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE token(YCSB_KEY) >= token(?) LIMIT ?`, strings.Join(fields, ","), db.keySpace, table)
+	return db.queryRows(ctx, query, fields, startKey, count)
 }
 
 func (db *cassandraDB) execQuery(ctx context.Context, query string, args ...interface{}) error {
@@ -188,66 +278,226 @@ func (db *cassandraDB) execQuery(ctx context.Context, query string, args ...inte
 	return err
 }
 
-func (db *cassandraDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
-	buf := db.bufPool.Get()
-	defer db.bufPool.Put(buf)
+// execWrite runs the write with the write consistency. With cassandra.lwt, the write is a
+// lightweight transaction and returns ErrNotApplied if its condition is not met.
+func (db *cassandraDB) execWrite(ctx context.Context, query string, args ...interface{}) error {
+	if db.verbose {
+		fmt.Printf("%s %v\n", query, args)
+	}
+
+	q := db.session.Query(query, args...).WithContext(ctx).Consistency(db.writeConsistency)
+	if !db.lwt {
+		return q.Exec()
+	}
+
+	applied, err := q.MapScanCAS(make(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return ErrNotApplied
+	}
+	return nil
+}
+
+// execBatch runs the writes in a batch of cassandra.batch. The conditional batches can
+// only write one partition, so the lightweight transactions run one by one.
+func (db *cassandraDB) execBatch(ctx context.Context, queries []string, args [][]interface{}) error {
+	if db.lwt {
+		for i, query := range queries {
+			if err := db.execWrite(ctx, query, args[i]...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if db.verbose {
+		for i, query := range queries {
+			fmt.Printf("%s %v\n", query, args[i])
+		}
+	}
+
+	b := db.session.NewBatch(db.batchType).WithContext(ctx)
+	b.SetConsistency(db.writeConsistency)
+	for i, query := range queries {
+		b.Query(query, args[i]...)
+	}
+	return db.session.ExecuteBatch(b)
+}
+
+func (db *cassandraDB) updateQuery(table string, key string, values map[string][]byte) (string, []interface{}) {
+	pairs := util.NewFieldPairs(values)
+	fields := make([]string, 0, len(pairs))
+	args := make([]interface{}, 0, len(pairs)+1)
+	for _, p := range pairs {
+		fields = append(fields, p.Field)
+		args = append(args, p.Value)
+	}
+	args = append(args, key)
 
+	buf := new(bytes.Buffer)
 	buf.WriteString("UPDATE ")
 	buf.WriteString(fmt.Sprintf("%s.%s", db.keySpace, table))
 	buf.WriteString(" SET ")
-	firstField := true
-	pairs := util.NewFieldPairs(values)
-	args := make([]interface{}, 0, len(values)+1)
-	for _, p := range pairs {
-		if firstField {
-			firstField = false
-		} else {
+	for i, field := range fields {
+		if i > 0 {
 			buf.WriteString(", ")
 		}
-
-		buf.WriteString(p.Field)
+		buf.WriteString(field)
 		buf.WriteString(`= ?`)
-		args = append(args, p.Value)
 	}
 	buf.WriteString(" WHERE YCSB_KEY = ?")
+	if db.lwt {
+		buf.WriteString(" IF EXISTS")
+	}
+	return buf.String(), args
+}
 
-	args = append(args, key)
+func (db *cassandraDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	query, args := db.updateQuery(table, key, values)
+	return db.execWrite(ctx, query, args...)
+}
 
-	return db.execQuery(ctx, buf.String(), args...)
+func (db *cassandraDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	queries := make([]string, 0, len(keys))
+	args := make([][]interface{}, 0, len(keys))
+	for i, key := range keys {
+		query, arg := db.updateQuery(table, key, values[i])
+		queries = append(queries, query)
+		args = append(args, arg)
+	}
+	return db.execBatch(ctx, queries, args)
 }
 
-func (db *cassandraDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
-	args := make([]interface{}, 0, 1+len(values))
+func (db *cassandraDB) insertQuery(table string, key string, values map[string][]byte) (string, []interface{}) {
+	pairs := util.NewFieldPairs(values)
+	fields := make([]string, 0, len(pairs))
+	args := make([]interface{}, 0, 1+len(pairs))
 	args = append(args, key)
+	for _, p := range pairs {
+		fields = append(fields, p.Field)
+		args = append(args, p.Value)
+	}
 
-	buf := db.bufPool.Get()
-	defer db.bufPool.Put(buf)
-
+	buf := new(bytes.Buffer)
 	buf.WriteString("INSERT INTO ")
 	buf.WriteString(fmt.Sprintf("%s.%s", db.keySpace, table))
 	buf.WriteString(" (YCSB_KEY")
-
-	pairs := util.NewFieldPairs(values)
-	for _, p := range pairs {
-		args = append(args, p.Value)
+	for _, field := range fields {
 		buf.WriteString(" ,")
-		buf.WriteString(p.Field)
+		buf.WriteString(field)
 	}
 	buf.WriteString(") VALUES (?")
-
-	for i := 0; i < len(pairs); i++ {
+	for i := 0; i < len(fields); i++ {
 		buf.WriteString(" ,?")
 	}
-
 	buf.WriteByte(')')
+	if db.lwt {
+		buf.WriteString(" IF NOT EXISTS")
+	}
+	return buf.String(), args
+}
 
-	return db.execQuery(ctx, buf.String(), args...)
+func (db *cassandraDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	query, args := db.insertQuery(table, key, values)
+	return db.execWrite(ctx, query, args...)
 }
 
-func (db *cassandraDB) Delete(ctx context.Context, table string, key string) error {
+func (db *cassandraDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	queries := make([]string, 0, len(keys))
+	args := make([][]interface{}, 0, len(keys))
+	for i, key := range keys {
+		query, arg := db.insertQuery(table, key, values[i])
+		queries = append(queries, query)
+		args = append(args, arg)
+	}
+	return db.execBatch(ctx, queries, args)
+}
+
+func (db *cassandraDB) deleteQuery(table string) string {
 	query := fmt.Sprintf(`DELETE FROM %s.%s WHERE YCSB_KEY = ?`, db.keySpace, table)
+	if db.lwt {
+		query += " IF EXISTS"
+	}
+	return query
+}
+
+func (db *cassandraDB) Delete(ctx context.Context, table string, key string) error {
+	return db.execWrite(ctx, db.deleteQuery(table), key)
+}
+
+func (db *cassandraDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	queries := make([]string, 0, len(keys))
+	args := make([][]interface{}, 0, len(keys))
+	for _, key := range keys {
+		queries = append(queries, db.deleteQuery(table))
+		args = append(args, []interface{}{key})
+	}
+	return db.execBatch(ctx, queries, args)
+}
+
+func (db *cassandraDB) CreateIndex(ctx context.Context, table string, field string) error {
+	query := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS ON %s.%s (%s)`, db.keySpace, table, field)
+
+	return db.execQuery(ctx, query)
+}
+
+func (db *cassandraDB) queryRows(ctx context.Context, query string, fields []string, args ...interface{}) ([]map[string][]byte, error) {
+	if db.verbose {
+		fmt.Printf("%s %v\n", query, args)
+	}
+
+	iter := db.session.Query(query, args...).WithContext(ctx).Consistency(db.readConsistency).Iter()
+	var rows []map[string][]byte
+	for {
+		dest := make([]interface{}, len(fields))
+		for i := 0; i < len(fields); i++ {
+			dest[i] = new([]byte)
+		}
+		if !iter.Scan(dest...) {
+			break
+		}
+
+		m := make(map[string][]byte, len(fields))
+		for i, v := range dest {
+			m[fields[i]] = *v.(*[]byte)
+		}
+		rows = append(rows, m)
+	}
+
+	return rows, iter.Close()
+}
+
+// Query uses the secondary index on the field.
+func (db *cassandraDB) Query(ctx context.Context, table string, field string, value []byte, fields []string) ([]map[string][]byte, error) {
+	if len(fields) == 0 {
+		fields = db.fieldNames
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE %s = ?`, strings.Join(fields, ","), db.keySpace, table, field)
+	return db.queryRows(ctx, query, fields, value)
+}
+
+// RangeQuery filters on the field, Cassandra doesn't order the result by a secondary index.
+func (db *cassandraDB) RangeQuery(ctx context.Context, table string, field string, start []byte, count int, fields []string) ([]map[string][]byte, error) {
+	if len(fields) == 0 {
+		fields = db.fieldNames
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s.%s WHERE %s >= ? LIMIT ? ALLOW FILTERING`, strings.Join(fields, ","), db.keySpace, table, field)
+	return db.queryRows(ctx, query, fields, start, count)
+}
+
+func (db *cassandraDB) Count(ctx context.Context, table string, field string, start []byte, end []byte) (int64, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s.%s WHERE %s >= ? AND %s <= ? ALLOW FILTERING`, db.keySpace, table, field, field)
+	if db.verbose {
+		fmt.Printf("%s %v\n", query, []interface{}{start, end})
+	}
 
-	return db.execQuery(ctx, query, key)
+	var n int64
+	err := db.session.Query(query, start, end).WithContext(ctx).Consistency(db.readConsistency).Scan(&n)
+	return n, err
 }
 
 func init() {
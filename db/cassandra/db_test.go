@@ -0,0 +1,210 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassandra
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/magiconair/properties"
+)
+
+const testKeyspace = "ycsb_test"
+
+// newTestDB connects to the cassandra of $CASSANDRA_CLUSTER, 127.0.0.1:9042 by default, and
+// creates the table usertable with the properties. The test is skipped if no cassandra is
+// reachable.
+func newTestDB(t *testing.T, s string) *cassandraDB {
+	cluster := os.Getenv("CASSANDRA_CLUSTER")
+	if cluster == "" {
+		cluster = cassandraClusterDefault
+	}
+
+	session, err := gocql.NewCluster(strings.Split(cluster, ",")...).CreateSession()
+	if err != nil {
+		t.Skipf("cassandra %s is not reachable: %v", cluster, err)
+	}
+	defer session.Close()
+	// The replication factor 1 can't achieve the consistency level TWO.
+	if err = session.Query(fmt.Sprintf(`CREATE KEYSPACE IF NOT EXISTS %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': 1}`, testKeyspace)).Exec(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := properties.MustLoadString(s)
+	p.Set(cassandraCluster, cluster)
+	p.Set(cassandraKeyspace, testKeyspace)
+	p.Set(cassandraReadConsistency, "ONE")
+	p.Set("table", "usertable")
+	p.Set("fieldcount", "2")
+	p.Set("dropdata", "true")
+	if _, ok := p.Get(cassandraWriteConsistency); !ok {
+		p.Set(cassandraWriteConsistency, "ONE")
+	}
+
+	db, err := cassandraCreator{}.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.(*cassandraDB)
+}
+
+func row(v string) map[string][]byte {
+	return map[string][]byte{"field0": []byte(v), "field1": []byte(v + v)}
+}
+
+func TestQueries(t *testing.T) {
+	db := &cassandraDB{keySpace: "test"}
+	values := map[string][]byte{"field1": []byte("b"), "field0": []byte("a")}
+
+	query, args := db.insertQuery("usertable", "k1", values)
+	if want := "INSERT INTO test.usertable (YCSB_KEY ,field0 ,field1) VALUES (? ,? ,?)"; query != want {
+		t.Fatalf("want %s, but got %s", want, query)
+	}
+	if want := []interface{}{"k1", []byte("a"), []byte("b")}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("want %q, but got %q", want, args)
+	}
+
+	query, args = db.updateQuery("usertable", "k1", values)
+	if want := "UPDATE test.usertable SET field0= ?, field1= ? WHERE YCSB_KEY = ?"; query != want {
+		t.Fatalf("want %s, but got %s", want, query)
+	}
+	if want := []interface{}{[]byte("a"), []byte("b"), "k1"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("want %q, but got %q", want, args)
+	}
+}
+
+func TestLWTQueries(t *testing.T) {
+	db := &cassandraDB{keySpace: "test", lwt: true}
+	values := map[string][]byte{"field0": []byte("a")}
+
+	if query, _ := db.insertQuery("usertable", "k1", values); query != "INSERT INTO test.usertable (YCSB_KEY ,field0) VALUES (? ,?) IF NOT EXISTS" {
+		t.Fatalf("got insert %s", query)
+	}
+	if query, _ := db.updateQuery("usertable", "k1", values); query != "UPDATE test.usertable SET field0= ? WHERE YCSB_KEY = ? IF EXISTS" {
+		t.Fatalf("got update %s", query)
+	}
+	if query := db.deleteQuery("usertable"); query != "DELETE FROM test.usertable WHERE YCSB_KEY = ? IF EXISTS" {
+		t.Fatalf("got delete %s", query)
+	}
+}
+
+func TestConsistency(t *testing.T) {
+	p := properties.MustLoadString("cassandra.readconsistency=TWO_AND_A_HALF")
+	if _, err := (cassandraCreator{}).Create(p); err == nil {
+		t.Fatal("want an error for the unknown consistency level")
+	}
+
+	db := newTestDB(t, "cassandra.writeconsistency=TWO")
+	defer db.Close()
+	if err := db.Insert(context.Background(), "usertable", "k1", row("a")); err == nil {
+		t.Fatal("want an error for writing with more replicas than the keyspace has")
+	}
+}
+
+func TestBatchRead(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, "")
+	defer db.Close()
+
+	keys := []string{"a", "b", "c"}
+	if err := db.BatchInsert(ctx, "usertable", keys[:2], []map[string][]byte{row("a"), row("b")}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.BatchRead(ctx, "usertable", keys, []string{"field1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []map[string][]byte{{"field1": []byte("aa")}, {"field1": []byte("bb")}, nil}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+}
+
+func TestScan(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, "")
+	defer db.Close()
+
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("user%03d", i)
+		if err := db.Insert(ctx, "usertable", key, row(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The rows are in the token order, so the scan from the key of the smallest token
+	// reads all the rows.
+	all, err := db.queryRows(ctx, fmt.Sprintf("SELECT YCSB_KEY FROM %s.usertable", testKeyspace), []string{"YCSB_KEY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 50 {
+		t.Fatalf("want 50 rows, but got %d", len(all))
+	}
+
+	rows, err := db.Scan(ctx, "usertable", string(all[0]["YCSB_KEY"]), 100, []string{"field0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 50 {
+		t.Fatalf("want 50 rows, but got %d", len(rows))
+	}
+
+	rows, err = db.Scan(ctx, "usertable", string(all[10]["YCSB_KEY"]), 20, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 20 {
+		t.Fatalf("want 20 rows, but got %d", len(rows))
+	}
+	for i, m := range rows {
+		if key := all[10+i]["YCSB_KEY"]; !reflect.DeepEqual(m, row(string(key))) {
+			t.Fatalf("want row %d of %s, but got %q", i, key, m)
+		}
+	}
+}
+
+func TestLWT(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, "cassandra.lwt=true")
+	defer db.Close()
+
+	if err := db.Insert(ctx, "usertable", "k1", row("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Insert(ctx, "usertable", "k1", row("b")); err != ErrNotApplied {
+		t.Fatalf("want ErrNotApplied for inserting an existing key, but got %v", err)
+	}
+	if err := db.BatchInsert(ctx, "usertable", []string{"k2", "k1"}, []map[string][]byte{row("c"), row("d")}); err != ErrNotApplied {
+		t.Fatalf("want ErrNotApplied for batch inserting an existing key, but got %v", err)
+	}
+	if err := db.Update(ctx, "usertable", "k3", row("e")); err != ErrNotApplied {
+		t.Fatalf("want ErrNotApplied for updating a missing key, but got %v", err)
+	}
+	if err := db.Delete(ctx, "usertable", "k3"); err != ErrNotApplied {
+		t.Fatalf("want ErrNotApplied for deleting a missing key, but got %v", err)
+	}
+
+	if m, err := db.Read(ctx, "usertable", "k1", nil); err != nil || !reflect.DeepEqual(m, row("a")) {
+		t.Fatalf("read k1 got %q, %v", m, err)
+	}
+	if m, err := db.Read(ctx, "usertable", "k3", nil); err != nil || m != nil {
+		t.Fatalf("read the missing k3 got %q, %v", m, err)
+	}
+}
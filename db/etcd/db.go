@@ -0,0 +1,345 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// properties
+const (
+	etcdEndpoints             = "etcd.endpoints"
+	etcdDialTimeout           = "etcd.dial_timeout"
+	etcdUsername              = "etcd.username"
+	etcdPassword              = "etcd.password"
+	etcdCAS                   = "etcd.cas"
+	etcdSerializable          = "etcd.serializable"
+	etcdTLSCA                 = "etcd.tls_ca"
+	etcdTLSCert               = "etcd.tls_cert"
+	etcdTLSKey                = "etcd.tls_key"
+	etcdTLSInsecureSkipVerify = "etcd.tls_insecure_skip_verify"
+	etcdMaxTxnOps             = "etcd.max_txn_ops"
+)
+
+// ErrConflict is returned by the compare-and-swap writes if the record is changed or
+// inserted by others.
+var ErrConflict = errors.New("etcd: compare-and-swap conflict")
+
+type etcdCreator struct {
+}
+
+type etcdDB struct {
+	p *properties.Properties
+
+	client *clientv3.Client
+
+	r       *util.RowCodec
+	bufPool *util.BufPool
+
+	cas       bool
+	getOpts   []clientv3.OpOption
+	maxTxnOps int
+}
+
+func (c etcdCreator) Create(p *properties.Properties) (ycsb.DB, error) {
+	cfg := clientv3.Config{
+		Endpoints:   strings.Split(p.GetString(etcdEndpoints, "localhost:2379"), ","),
+		DialTimeout: p.GetParsedDuration(etcdDialTimeout, 2*time.Second),
+		Username:    p.GetString(etcdUsername, ""),
+		Password:    p.GetString(etcdPassword, ""),
+	}
+
+	caPath := p.GetString(etcdTLSCA, "")
+	certPath := p.GetString(etcdTLSCert, "")
+	keyPath := p.GetString(etcdTLSKey, "")
+	insecureSkipVerify := p.GetBool(etcdTLSInsecureSkipVerify, false)
+	if len(caPath) > 0 || len(certPath) > 0 || insecureSkipVerify {
+		tlsConfig, err := util.CreateTLSConfig(caPath, certPath, keyPath, insecureSkipVerify)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TLS = tlsConfig
+	}
+
+	client, err := clientv3.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &etcdDB{
+		p:         p,
+		client:    client,
+		r:         util.NewRowCodec(p),
+		bufPool:   util.NewBufPool(),
+		cas:       p.GetBool(etcdCAS, false),
+		maxTxnOps: p.GetInt(etcdMaxTxnOps, 128),
+	}
+	if db.maxTxnOps <= 0 {
+		client.Close()
+		return nil, fmt.Errorf("%s must be positive", etcdMaxTxnOps)
+	}
+	if p.GetBool(etcdSerializable, false) {
+		db.getOpts = append(db.getOpts, clientv3.WithSerializable())
+	}
+	return db, nil
+}
+
+func (db *etcdDB) Close() error {
+	return db.client.Close()
+}
+
+func (db *etcdDB) InitThread(ctx context.Context, _ int, _ int) context.Context {
+	return ctx
+}
+
+func (db *etcdDB) CleanupThread(_ context.Context) {
+}
+
+func (db *etcdDB) getRowKey(table string, key string) string {
+	return fmt.Sprintf("%s:%s", table, key)
+}
+
+// txn runs the ops in the transactions of at most maxTxnOps ops, the limit of the server.
+// The compares of the ops are in the same transaction as the ops, and the transaction
+// fails with ErrConflict if any compare fails. The earlier transactions are not rolled back.
+func (db *etcdDB) txn(ctx context.Context, cmps []clientv3.Cmp, ops []clientv3.Op) ([]*etcdserverpb.ResponseOp, error) {
+	responses := make([]*etcdserverpb.ResponseOp, 0, len(ops))
+	for start := 0; start < len(ops); start += db.maxTxnOps {
+		end := start + db.maxTxnOps
+		if end > len(ops) {
+			end = len(ops)
+		}
+		var chunkCmps []clientv3.Cmp
+		if len(cmps) > 0 {
+			chunkCmps = cmps[start:end]
+		}
+
+		resp, err := db.client.Txn(ctx).If(chunkCmps...).Then(ops[start:end]...).Commit()
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Succeeded {
+			return nil, ErrConflict
+		}
+		responses = append(responses, resp.Responses...)
+	}
+	return responses, nil
+}
+
+// get reads the rows of the keys in transactions, and returns their mod revisions for
+// the compare-and-swap writes.
+func (db *etcdDB) get(ctx context.Context, rowKeys []string, fields []string) ([]map[string][]byte, []int64, error) {
+	ops := make([]clientv3.Op, 0, len(rowKeys))
+	for _, rowKey := range rowKeys {
+		ops = append(ops, clientv3.OpGet(rowKey, db.getOpts...))
+	}
+
+	responses, err := db.txn(ctx, nil, ops)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([]map[string][]byte, 0, len(rowKeys))
+	revs := make([]int64, 0, len(rowKeys))
+	for i, r := range responses {
+		kvs := r.GetResponseRange().Kvs
+		if len(kvs) == 0 {
+			return nil, nil, fmt.Errorf("key not found: %s", rowKeys[i])
+		}
+
+		m, err := db.r.Decode(kvs[0].Value, fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, m)
+		revs = append(revs, kvs[0].ModRevision)
+	}
+	return rows, revs, nil
+}
+
+// lastIndices returns the index of the last occurrence of every key in the order of the
+// keys, because a transaction can't write the same key twice.
+func lastIndices(keys []string) []int {
+	last := make(map[string]int, len(keys))
+	for i, key := range keys {
+		last[key] = i
+	}
+
+	indices := make([]int, 0, len(last))
+	for i, key := range keys {
+		if last[key] == i {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func (db *etcdDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	rowKey := db.getRowKey(table, key)
+	resp, err := db.client.Get(ctx, rowKey, db.getOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, fmt.Errorf("key %w: %s", ycsb.ErrNotFound, rowKey)
+	}
+
+	return db.r.Decode(resp.Kvs[0].Value, fields)
+}
+
+func (db *etcdDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	rowKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		rowKeys = append(rowKeys, db.getRowKey(table, key))
+	}
+
+	rows, _, err := db.get(ctx, rowKeys, fields)
+	return rows, err
+}
+
+func (db *etcdDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(ctx, db.getRowKey(table, startKey), clientv3.GetPrefixRangeEnd(table+":"), count, fields)
+}
+
+func (db *etcdDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(ctx, db.getRowKey(table, startKey), db.getRowKey(table, endKey), limit, fields)
+}
+
+// scan reads at most count rows in [rowStartKey, rowEndKey) by a range Get in the key order.
+func (db *etcdDB) scan(ctx context.Context, rowStartKey string, rowEndKey string, count int, fields []string) ([]map[string][]byte, error) {
+	opts := append([]clientv3.OpOption{clientv3.WithRange(rowEndKey), clientv3.WithLimit(int64(count))}, db.getOpts...)
+	resp, err := db.client.Get(ctx, rowStartKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]map[string][]byte, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		m, err := db.r.Decode(kv.Value, fields)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// put writes the rows in transactions, and the last row of the same key wins. With
+// etcd.cas, the rows are only written if their mod revisions are not changed, and the
+// revision 0 means the key doesn't exist.
+func (db *etcdDB) put(ctx context.Context, rowKeys []string, rows []map[string][]byte, revs []int64) error {
+	var cmps []clientv3.Cmp
+	ops := make([]clientv3.Op, 0, len(rowKeys))
+	for _, i := range lastIndices(rowKeys) {
+		rowKey := rowKeys[i]
+		buf := db.bufPool.Get()
+		rowData, err := db.r.Encode(buf.Bytes(), rows[i])
+		if err != nil {
+			db.bufPool.Put(buf)
+			return err
+		}
+		// The op keeps the row as a string, so the buffer can be reused.
+		ops = append(ops, clientv3.OpPut(rowKey, string(rowData)))
+		db.bufPool.Put(buf)
+
+		if db.cas {
+			cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(rowKey), "=", revs[i]))
+		}
+	}
+
+	if len(ops) == 1 && len(cmps) == 0 {
+		_, err := db.client.Do(ctx, ops[0])
+		return err
+	}
+
+	_, err := db.txn(ctx, cmps, ops)
+	return err
+}
+
+func (db *etcdDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	return db.BatchUpdate(ctx, table, []string{key}, []map[string][]byte{values})
+}
+
+func (db *etcdDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	rowKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		rowKeys = append(rowKeys, db.getRowKey(table, key))
+	}
+
+	rows, revs, err := db.get(ctx, rowKeys, nil)
+	if err != nil {
+		return err
+	}
+
+	// The updates of the same key are applied to the same row.
+	first := make(map[string]int, len(rowKeys))
+	for i, rowKey := range rowKeys {
+		if j, ok := first[rowKey]; ok {
+			rows[i] = rows[j]
+		} else {
+			first[rowKey] = i
+		}
+		for field, value := range values[i] {
+			rows[i][field] = value
+		}
+	}
+	return db.put(ctx, rowKeys, rows, revs)
+}
+
+func (db *etcdDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	return db.BatchInsert(ctx, table, []string{key}, []map[string][]byte{values})
+}
+
+func (db *etcdDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	rowKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		rowKeys = append(rowKeys, db.getRowKey(table, key))
+	}
+
+	return db.put(ctx, rowKeys, values, make([]int64, len(keys)))
+}
+
+func (db *etcdDB) Delete(ctx context.Context, table string, key string) error {
+	_, err := db.client.Delete(ctx, db.getRowKey(table, key))
+	return err
+}
+
+func (db *etcdDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	ops := make([]clientv3.Op, 0, len(keys))
+	for _, i := range lastIndices(keys) {
+		ops = append(ops, clientv3.OpDelete(db.getRowKey(table, keys[i])))
+	}
+
+	_, err := db.txn(ctx, nil, ops)
+	return err
+}
+
+func (db *etcdDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	_, err := db.client.Delete(ctx, db.getRowKey(table, startKey), clientv3.WithRange(db.getRowKey(table, endKey)))
+	return err
+}
+
+func init() {
+	ycsb.RegisterDBCreator("etcd", etcdCreator{})
+}
@@ -0,0 +1,180 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/embed"
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/dbtest"
+)
+
+// testMaxTxnOps is the --max-txn-ops of the test server, small to test the large batches.
+const testMaxTxnOps = 8
+
+func freeURL(t *testing.T) url.URL {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+// newTestDB starts an embedded etcd server, and connects to it with the properties.
+func newTestDB(t *testing.T, s string) (*etcdDB, func()) {
+	dir, err := ioutil.TempDir("", "etcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := embed.NewConfig()
+	cfg.Dir = dir
+	clientURL, peerURL := freeURL(t), freeURL(t)
+	cfg.LCUrls, cfg.ACUrls = []url.URL{clientURL}, []url.URL{clientURL}
+	cfg.LPUrls, cfg.APUrls = []url.URL{peerURL}, []url.URL{peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+	cfg.MaxTxnOps = testMaxTxnOps
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	clean := func() {
+		e.Close()
+		os.RemoveAll(dir)
+	}
+
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		clean()
+		t.Fatal("etcd server isn't ready")
+	}
+
+	p := properties.MustLoadString(s)
+	p.Set(etcdEndpoints, clientURL.Host)
+	p.Set("fieldcount", "2")
+	db, err := etcdCreator{}.Create(p)
+	if err != nil {
+		clean()
+		t.Fatal(err)
+	}
+
+	return db.(*etcdDB), func() {
+		db.Close()
+		clean()
+	}
+}
+
+func TestCRUD(t *testing.T) {
+	db, clean := newTestDB(t, "")
+	defer clean()
+	dbtest.TestCRUD(t, db)
+}
+
+func TestScan(t *testing.T) {
+	db, clean := newTestDB(t, "")
+	defer clean()
+	dbtest.TestScan(t, db)
+}
+
+func TestBatch(t *testing.T) {
+	db, clean := newTestDB(t, "")
+	defer clean()
+	dbtest.TestBatch(t, db)
+}
+
+func TestCAS(t *testing.T) {
+	ctx := context.Background()
+	db, clean := newTestDB(t, "etcd.cas=true")
+	defer clean()
+
+	if err := db.Insert(ctx, "t", "k1", dbtest.Row("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Insert(ctx, "t", "k1", dbtest.Row("b")); err != ErrConflict {
+		t.Fatalf("want the conflict for inserting an existing key, but got %v", err)
+	}
+
+	// The record is changed after it is read by the update.
+	rows, revs, err := db.get(ctx, []string{"t:k1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Update(ctx, "t", "k1", map[string][]byte{"field0": []byte("c")}); err != nil {
+		t.Fatal(err)
+	}
+	if err = db.put(ctx, []string{"t:k1"}, rows, revs); err != ErrConflict {
+		t.Fatalf("want the conflict for updating a changed record, but got %v", err)
+	}
+	if m, err := db.Read(ctx, "t", "k1", []string{"field0"}); err != nil || string(m["field0"]) != "c" {
+		t.Fatalf("read field0 got %q, %v", m, err)
+	}
+}
+
+func TestLargeBatch(t *testing.T) {
+	ctx := context.Background()
+	db, clean := newTestDB(t, fmt.Sprintf("etcd.cas=true\netcd.max_txn_ops=%d", testMaxTxnOps))
+	defer clean()
+
+	// The batches are larger than a transaction, and have the duplicate keys.
+	var keys []string
+	var rows, updates []map[string][]byte
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("k%02d", i%15)
+		keys = append(keys, key)
+		rows = append(rows, dbtest.Row(key))
+		updates = append(updates, map[string][]byte{fmt.Sprintf("field%d", i/15): []byte("u")})
+	}
+	if err := db.BatchInsert(ctx, "t", keys[:15], rows[:15]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.BatchUpdate(ctx, "t", keys, updates); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := db.BatchRead(ctx, "t", keys[:15], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range got {
+		want := map[string][]byte{"field0": []byte("u"), "field1": []byte(keys[i] + keys[i])}
+		if i < 5 {
+			want["field1"] = []byte("u")
+		}
+		if !reflect.DeepEqual(m, want) {
+			t.Fatalf("want row %d %q, but got %q", i, want, m)
+		}
+	}
+
+	if err = db.BatchDelete(ctx, "t", keys); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if _, err = db.Read(ctx, "t", key, nil); err == nil {
+			t.Fatalf("want an error for reading the deleted key %s", key)
+		}
+	}
+}
@@ -95,12 +95,21 @@ func (db *fDB) Read(ctx context.Context, table string, key string, fields []stri
 }
 
 func (db *fDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
-	rowKey := db.getRowKey(table, startKey)
+	return db.scan(fdb.KeyRange{
+		Begin: fdb.Key(db.getRowKey(table, startKey)),
+		End:   fdb.Key(db.getEndRowKey(table)),
+	}, count, fields)
+}
+
+func (db *fDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(fdb.KeyRange{
+		Begin: fdb.Key(db.getRowKey(table, startKey)),
+		End:   fdb.Key(db.getRowKey(table, endKey)),
+	}, limit, fields)
+}
+
+func (db *fDB) scan(r fdb.KeyRange, count int, fields []string) ([]map[string][]byte, error) {
 	res, err := db.db.Transact(func(tr fdb.Transaction) (interface{}, error) {
-		r := fdb.KeyRange{
-			Begin: fdb.Key(rowKey),
-			End:   fdb.Key(db.getEndRowKey(table)),
-		}
 		ri := tr.GetRange(r, fdb.RangeOptions{Limit: count}).Iterator()
 		res := make([]map[string][]byte, 0, count)
 		for ri.Advance() {
@@ -191,6 +200,18 @@ func (db *fDB) Delete(ctx context.Context, table string, key string) error {
 	return err
 }
 
+func (db *fDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	r := fdb.KeyRange{
+		Begin: fdb.Key(db.getRowKey(table, startKey)),
+		End:   fdb.Key(db.getRowKey(table, endKey)),
+	}
+	_, err := db.db.Transact(func(tr fdb.Transaction) (ret interface{}, e error) {
+		tr.ClearRange(r)
+		return
+	})
+	return err
+}
+
 type fdbCreator struct {
 }
 
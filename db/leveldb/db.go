@@ -0,0 +1,300 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leveldb
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	lutil "github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// properties
+const (
+	leveldbDir                    = "leveldb.dir"
+	leveldbWriteBuffer            = "leveldb.write_buffer"
+	leveldbBlockCacheCapacity     = "leveldb.block_cache_capacity"
+	leveldbBlockSize              = "leveldb.block_size"
+	leveldbBlockRestartInterval   = "leveldb.block_restart_interval"
+	leveldbCompactionTableSize    = "leveldb.compaction_table_size"
+	leveldbCompactionTotalSize    = "leveldb.compaction_total_size"
+	leveldbOpenFilesCacheCapacity = "leveldb.open_files_cache_capacity"
+	leveldbBloomBitsPerKey        = "leveldb.bloom_bits_per_key"
+	leveldbCompression            = "leveldb.compression"
+	leveldbSyncWrites             = "leveldb.sync_writes"
+)
+
+type leveldbCreator struct {
+}
+
+type levelDB struct {
+	p *properties.Properties
+
+	db *leveldb.DB
+
+	r       *util.RowCodec
+	bufPool *util.BufPool
+
+	writeOpts *opt.WriteOptions
+}
+
+func (c leveldbCreator) Create(p *properties.Properties) (ycsb.DB, error) {
+	dir := p.GetString(leveldbDir, "/tmp/leveldb")
+
+	if p.GetBool(prop.DropData, prop.DropDataDefault) {
+		os.RemoveAll(dir)
+	}
+
+	opts, err := getOptions(p)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := leveldb.OpenFile(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &levelDB{
+		p:         p,
+		db:        db,
+		r:         util.NewRowCodec(p),
+		bufPool:   util.NewBufPool(),
+		writeOpts: &opt.WriteOptions{Sync: p.GetBool(leveldbSyncWrites, false)},
+	}, nil
+}
+
+func getOptions(p *properties.Properties) (*opt.Options, error) {
+	opts := &opt.Options{
+		WriteBuffer:            p.GetInt(leveldbWriteBuffer, 4<<20),
+		BlockCacheCapacity:     p.GetInt(leveldbBlockCacheCapacity, 8<<20),
+		BlockSize:              p.GetInt(leveldbBlockSize, 4<<10),
+		BlockRestartInterval:   p.GetInt(leveldbBlockRestartInterval, 16),
+		CompactionTableSize:    p.GetInt(leveldbCompactionTableSize, 2<<20),
+		CompactionTotalSize:    p.GetInt(leveldbCompactionTotalSize, 10<<20),
+		OpenFilesCacheCapacity: p.GetInt(leveldbOpenFilesCacheCapacity, 500),
+	}
+
+	switch c := p.GetString(leveldbCompression, "snappy"); c {
+	case "snappy":
+		opts.Compression = opt.SnappyCompression
+	case "none":
+		opts.Compression = opt.NoCompression
+	default:
+		return nil, fmt.Errorf("unknown %s %s", leveldbCompression, c)
+	}
+
+	if bitsPerKey := p.GetInt(leveldbBloomBitsPerKey, 10); bitsPerKey > 0 {
+		opts.Filter = filter.NewBloomFilter(bitsPerKey)
+	}
+
+	return opts, nil
+}
+
+func (db *levelDB) Close() error {
+	return db.db.Close()
+}
+
+func (db *levelDB) InitThread(ctx context.Context, _ int, _ int) context.Context {
+	return ctx
+}
+
+func (db *levelDB) CleanupThread(_ context.Context) {
+}
+
+func (db *levelDB) getRowKey(table string, key string) []byte {
+	return util.Slice(fmt.Sprintf("%s:%s", table, key))
+}
+
+func (db *levelDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	// The returned row is a copy owned by the caller.
+	row, err := db.db.Get(db.getRowKey(table, key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.r.Decode(row, fields)
+}
+
+func (db *levelDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	rows := make([]map[string][]byte, 0, len(keys))
+	for _, key := range keys {
+		m, err := db.Read(ctx, table, key, fields)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, m)
+	}
+
+	return rows, nil
+}
+
+func (db *levelDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
+	// The row keys of the table end before "<table>;", because ';' follows ':'.
+	return db.scan(db.getRowKey(table, startKey), []byte(table+";"), count, fields)
+}
+
+func (db *levelDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(db.getRowKey(table, startKey), db.getRowKey(table, endKey), limit, fields)
+}
+
+// scan reads at most count rows from rowStartKey, and stops before rowEndKey if it is not nil.
+func (db *levelDB) scan(rowStartKey []byte, rowEndKey []byte, count int, fields []string) ([]map[string][]byte, error) {
+	res := make([]map[string][]byte, 0, count)
+	it := db.db.NewIterator(&lutil.Range{Start: rowStartKey, Limit: rowEndKey}, nil)
+	defer it.Release()
+
+	for it.Next() && len(res) < count {
+		// The value of the iterator is reused by the next move.
+		m, err := db.r.Decode(append([]byte(nil), it.Value()...), fields)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// update merges the values into the stored row, and returns the encoded row in buf.
+func (db *levelDB) update(buf []byte, rowKey []byte, values map[string][]byte) ([]byte, error) {
+	row, err := db.db.Get(rowKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := db.r.Decode(row, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for field, value := range values {
+		m[field] = value
+	}
+
+	return db.r.Encode(buf, m)
+}
+
+func (db *levelDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	rowKey := db.getRowKey(table, key)
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	rowData, err := db.update(buf.Bytes(), rowKey, values)
+	if err != nil {
+		return err
+	}
+
+	return db.db.Put(rowKey, rowData, db.writeOpts)
+}
+
+func (db *levelDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	b := new(leveldb.Batch)
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	// The batch copies the row, so the buffer is reused for every row.
+	rowData := buf.Bytes()
+	for i, key := range keys {
+		rowKey := db.getRowKey(table, key)
+		var err error
+		if rowData, err = db.update(rowData, rowKey, values[i]); err != nil {
+			return err
+		}
+		b.Put(rowKey, rowData)
+	}
+
+	return db.db.Write(b, db.writeOpts)
+}
+
+func (db *levelDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	rowKey := db.getRowKey(table, key)
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	rowData, err := db.r.Encode(buf.Bytes(), values)
+	if err != nil {
+		return err
+	}
+
+	return db.db.Put(rowKey, rowData, db.writeOpts)
+}
+
+func (db *levelDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	b := new(leveldb.Batch)
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	// The batch copies the row, so the buffer is reused for every row.
+	rowData := buf.Bytes()
+	for i, key := range keys {
+		var err error
+		if rowData, err = db.r.Encode(rowData, values[i]); err != nil {
+			return err
+		}
+		b.Put(db.getRowKey(table, key), rowData)
+	}
+
+	return db.db.Write(b, db.writeOpts)
+}
+
+func (db *levelDB) Delete(ctx context.Context, table string, key string) error {
+	return db.db.Delete(db.getRowKey(table, key), db.writeOpts)
+}
+
+func (db *levelDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	b := new(leveldb.Batch)
+	for _, key := range keys {
+		b.Delete(db.getRowKey(table, key))
+	}
+
+	return db.db.Write(b, db.writeOpts)
+}
+
+// DeleteRange deletes the keys one by one in a batch because leveldb has no range deletion.
+func (db *levelDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	b := new(leveldb.Batch)
+	it := db.db.NewIterator(&lutil.Range{Start: db.getRowKey(table, startKey), Limit: db.getRowKey(table, endKey)}, nil)
+	defer it.Release()
+
+	for it.Next() {
+		b.Delete(it.Key())
+	}
+
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	return db.db.Write(b, db.writeOpts)
+}
+
+func init() {
+	ycsb.RegisterDBCreator("leveldb", leveldbCreator{})
+}
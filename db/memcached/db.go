@@ -0,0 +1,334 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memcached
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// properties
+const (
+	memcachedServers      = "memcached.servers"
+	memcachedProtocol     = "memcached.protocol"
+	memcachedTimeout      = "memcached.timeout"
+	memcachedMaxIdleConns = "memcached.max_idle_conns"
+	memcachedExpiration   = "memcached.expiration"
+)
+
+// ErrScanNotSupported is returned by Scan, because memcached can't read the keys in order.
+var ErrScanNotSupported = fmt.Errorf("memcached: scan is %w", ycsb.ErrNotSupported)
+
+type memcachedCreator struct {
+}
+
+type conn struct {
+	nc net.Conn
+	rw *bufio.ReadWriter
+}
+
+// server keeps the idle connections of a server.
+type server struct {
+	addr    string
+	maxIdle int
+
+	mu   sync.Mutex
+	idle []*conn
+}
+
+func (s *server) get(timeout time.Duration) (*conn, error) {
+	s.mu.Lock()
+	if n := len(s.idle); n > 0 {
+		c := s.idle[n-1]
+		s.idle = s.idle[:n-1]
+		s.mu.Unlock()
+		return c, nil
+	}
+	s.mu.Unlock()
+
+	nc, err := net.DialTimeout("tcp", s.addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{nc: nc, rw: bufio.NewReadWriter(bufio.NewReader(nc), bufio.NewWriter(nc))}, nil
+}
+
+// put returns the connection after it is used. The connection is closed if the command
+// fails, because the rest of the reply may be still unread.
+func (s *server) put(c *conn, err error) {
+	if _, ok := err.(keyError); err != nil && err != ErrCacheMiss && !ok {
+		c.nc.Close()
+		return
+	}
+
+	s.mu.Lock()
+	if len(s.idle) < s.maxIdle {
+		s.idle = append(s.idle, c)
+		c = nil
+	}
+	s.mu.Unlock()
+
+	if c != nil {
+		c.nc.Close()
+	}
+}
+
+func (s *server) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.idle {
+		c.nc.Close()
+	}
+	s.idle = nil
+}
+
+type memcachedDB struct {
+	p *properties.Properties
+
+	servers []*server
+	ring    *ring
+	proto   protocol
+
+	timeout    time.Duration
+	expiration uint32
+
+	r       *util.RowCodec
+	bufPool *util.BufPool
+}
+
+func (c memcachedCreator) Create(p *properties.Properties) (ycsb.DB, error) {
+	var proto protocol
+	switch p.GetString(memcachedProtocol, "text") {
+	case "text":
+		// The text protocol separates the keys with spaces and lines.
+		if strings.ToLower(p.GetString(prop.KeyFormat, prop.KeyFormatDefault)) == "binary" {
+			return nil, fmt.Errorf("the memcached text protocol doesn't support keyformat=binary")
+		}
+		proto = textProtocol{}
+	case "binary":
+		proto = binaryProtocol{}
+	default:
+		return nil, fmt.Errorf("unsupported memcached protocol %s", p.GetString(memcachedProtocol, ""))
+	}
+
+	addrs := strings.Split(p.GetString(memcachedServers, "localhost:11211"), ",")
+	servers := make([]*server, 0, len(addrs))
+	for i, addr := range addrs {
+		addrs[i] = strings.TrimSpace(addr)
+		servers = append(servers, &server{
+			addr:    addrs[i],
+			maxIdle: p.GetInt(memcachedMaxIdleConns, 16),
+		})
+	}
+
+	return &memcachedDB{
+		p:          p,
+		servers:    servers,
+		ring:       newRing(addrs),
+		proto:      proto,
+		timeout:    p.GetParsedDuration(memcachedTimeout, time.Second),
+		expiration: uint32(p.GetParsedDuration(memcachedExpiration, 0) / time.Second),
+		r:          util.NewRowCodec(p),
+		bufPool:    util.NewBufPool(),
+	}, nil
+}
+
+func (db *memcachedDB) Close() error {
+	for _, s := range db.servers {
+		s.close()
+	}
+	return nil
+}
+
+func (db *memcachedDB) InitThread(ctx context.Context, _ int, _ int) context.Context {
+	return ctx
+}
+
+func (db *memcachedDB) CleanupThread(_ context.Context) {
+}
+
+func (db *memcachedDB) getRowKey(table string, key string) string {
+	return fmt.Sprintf("%s:%s", table, key)
+}
+
+// do runs f on a connection to the server, which must finish before the timeout and the
+// deadline of the context.
+func (db *memcachedDB) do(ctx context.Context, s *server, f func(rw *bufio.ReadWriter) error) error {
+	c, err := s.get(db.timeout)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(db.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err = c.nc.SetDeadline(deadline); err != nil {
+		c.nc.Close()
+		return err
+	}
+
+	err = f(c.rw)
+	s.put(c, err)
+	return err
+}
+
+func (db *memcachedDB) server(rowKey string) *server {
+	return db.servers[db.ring.pick(rowKey)]
+}
+
+func (db *memcachedDB) get(ctx context.Context, rowKey string, fields []string) (map[string][]byte, error) {
+	var values map[string][]byte
+	err := db.do(ctx, db.server(rowKey), func(rw *bufio.ReadWriter) (err error) {
+		values, err = db.proto.get(rw, []string{rowKey})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := values[rowKey]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return db.r.Decode(value, fields)
+}
+
+func (db *memcachedDB) set(ctx context.Context, rowKey string, values map[string][]byte) error {
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	rowData, err := db.r.Encode(buf.Bytes(), values)
+	if err != nil {
+		return err
+	}
+
+	return db.do(ctx, db.server(rowKey), func(rw *bufio.ReadWriter) error {
+		return db.proto.set(rw, rowKey, rowData, db.expiration)
+	})
+}
+
+func (db *memcachedDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	return db.get(ctx, db.getRowKey(table, key), fields)
+}
+
+// BatchRead reads the keys of every server by a multi-get.
+func (db *memcachedDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	rowKeys := make([]string, 0, len(keys))
+	groups := make(map[int][]string)
+	for _, key := range keys {
+		rowKey := db.getRowKey(table, key)
+		rowKeys = append(rowKeys, rowKey)
+		i := db.ring.pick(rowKey)
+		groups[i] = append(groups[i], rowKey)
+	}
+
+	values := make(map[string][]byte, len(keys))
+	for i, group := range groups {
+		err := db.do(ctx, db.servers[i], func(rw *bufio.ReadWriter) error {
+			res, err := db.proto.get(rw, group)
+			for rowKey, value := range res {
+				values[rowKey] = value
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows := make([]map[string][]byte, 0, len(keys))
+	for _, rowKey := range rowKeys {
+		value, ok := values[rowKey]
+		if !ok {
+			return nil, ErrCacheMiss
+		}
+		m, err := db.r.Decode(value, fields)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, m)
+	}
+	return rows, nil
+}
+
+func (db *memcachedDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
+	return nil, ErrScanNotSupported
+}
+
+func (db *memcachedDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	rowKey := db.getRowKey(table, key)
+	m, err := db.get(ctx, rowKey, nil)
+	if err != nil {
+		return err
+	}
+
+	for field, value := range values {
+		m[field] = value
+	}
+	return db.set(ctx, rowKey, m)
+}
+
+func (db *memcachedDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	for i, key := range keys {
+		if err := db.Update(ctx, table, key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *memcachedDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	return db.set(ctx, db.getRowKey(table, key), values)
+}
+
+func (db *memcachedDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	for i, key := range keys {
+		if err := db.Insert(ctx, table, key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *memcachedDB) Delete(ctx context.Context, table string, key string) error {
+	rowKey := db.getRowKey(table, key)
+	return db.do(ctx, db.server(rowKey), func(rw *bufio.ReadWriter) error {
+		return db.proto.delete(rw, rowKey)
+	})
+}
+
+func (db *memcachedDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	for _, key := range keys {
+		if err := db.Delete(ctx, table, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	ycsb.RegisterDBCreator("memcached", memcachedCreator{})
+}
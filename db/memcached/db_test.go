@@ -0,0 +1,354 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/dbtest"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// stubServer is a memcached server of the get, set and delete commands in the text and
+// binary protocols.
+type stubServer struct {
+	l net.Listener
+
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newStubServer(t *testing.T) *stubServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &stubServer{l: l, data: make(map[string][]byte)}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *stubServer) addr() string {
+	return s.l.Addr().String()
+}
+
+func (s *stubServer) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.data)
+}
+
+func (s *stubServer) serve(c net.Conn) {
+	defer c.Close()
+	rw := bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c))
+	for {
+		b, err := rw.Peek(1)
+		if err != nil {
+			return
+		}
+		if b[0] == binaryRequest {
+			err = s.serveBinary(rw)
+		} else {
+			err = s.serveText(rw)
+		}
+		if err == nil {
+			err = rw.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *stubServer) serveText(rw *bufio.ReadWriter) error {
+	line, err := readLine(rw.Reader)
+	if err != nil {
+		return err
+	}
+	parts := strings.Fields(string(line))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch parts[0] {
+	case "get":
+		for _, key := range parts[1:] {
+			if value, ok := s.data[key]; ok {
+				fmt.Fprintf(rw, "VALUE %s 0 %d\r\n%s\r\n", key, len(value), value)
+			}
+		}
+		rw.WriteString("END\r\n")
+	case "set":
+		size, _ := strconv.Atoi(parts[4])
+		value := make([]byte, size+2)
+		if _, err = io.ReadFull(rw, value); err != nil {
+			return err
+		}
+		s.data[parts[1]] = value[:size]
+		rw.WriteString("STORED\r\n")
+	case "delete":
+		if _, ok := s.data[parts[1]]; !ok {
+			rw.WriteString("NOT_FOUND\r\n")
+			break
+		}
+		delete(s.data, parts[1])
+		rw.WriteString("DELETED\r\n")
+	default:
+		rw.WriteString("ERROR\r\n")
+	}
+	return nil
+}
+
+func writeResponse(w io.Writer, opcode byte, status uint16, key []byte, value []byte) {
+	var buf bytes.Buffer
+	writeRequest(&buf, opcode, nil, string(key), value)
+	b := buf.Bytes()
+	b[0] = binaryResponse
+	b[6], b[7] = byte(status>>8), byte(status)
+	w.Write(b)
+}
+
+func (s *stubServer) serveBinary(rw *bufio.ReadWriter) error {
+	// The requests have the same layout as the responses except the magic.
+	var b [headerLen]byte
+	if _, err := io.ReadFull(rw, b[:]); err != nil {
+		return err
+	}
+	b[0] = binaryResponse
+	h, key, value, err := readResponse(io.MultiReader(bytes.NewReader(b[:]), rw))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch h.opcode {
+	case opGetKQ:
+		if value, ok := s.data[string(key)]; ok {
+			writeResponse(rw, opGetKQ, statusOK, key, value)
+		}
+	case opNoop:
+		writeResponse(rw, opNoop, statusOK, nil, nil)
+	case opSet:
+		s.data[string(key)] = append([]byte(nil), value...)
+		writeResponse(rw, opSet, statusOK, nil, nil)
+	case opDel:
+		if _, ok := s.data[string(key)]; !ok {
+			writeResponse(rw, opDel, statusKeyNotFound, nil, []byte("Not found"))
+			break
+		}
+		delete(s.data, string(key))
+		writeResponse(rw, opDel, statusOK, nil, nil)
+	default:
+		writeResponse(rw, h.opcode, 0x81, nil, []byte("Unknown command"))
+	}
+	return nil
+}
+
+func newTestDB(t *testing.T, proto string, servers ...*stubServer) ycsb.DB {
+	addrs := make([]string, 0, len(servers))
+	for _, s := range servers {
+		addrs = append(addrs, s.addr())
+	}
+
+	p := properties.NewProperties()
+	p.Set(memcachedServers, strings.Join(addrs, ","))
+	p.Set(memcachedProtocol, proto)
+	db, err := memcachedCreator{}.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func testCRUD(t *testing.T, proto string) {
+	ctx := context.Background()
+	s := newStubServer(t)
+	defer s.l.Close()
+	db := newTestDB(t, proto, s)
+	defer db.Close()
+
+	dbtest.TestCRUD(t, db)
+	if _, err := db.Read(ctx, "t", "k1", nil); err != ErrCacheMiss {
+		t.Fatalf("want the cache miss for reading a deleted key, but got %v", err)
+	}
+	if err := db.Delete(ctx, "t", "k1"); err != ErrCacheMiss {
+		t.Fatalf("want the cache miss for deleting a deleted key, but got %v", err)
+	}
+
+	_, err := db.Scan(ctx, "t", "k1", 10, nil)
+	if !errors.Is(err, ycsb.ErrNotSupported) {
+		t.Fatalf("want the unsupported scan, but got %v", err)
+	}
+}
+
+func TestText(t *testing.T) {
+	testCRUD(t, "text")
+}
+
+func TestBinary(t *testing.T) {
+	testCRUD(t, "binary")
+}
+
+func TestTextKeys(t *testing.T) {
+	ctx := context.Background()
+	s := newStubServer(t)
+	defer s.l.Close()
+	db := newTestDB(t, "text", s)
+	defer db.Close()
+
+	// The row key is "t:" and the key.
+	for _, key := range []string{"a b", "a\r\nflush_all", "a\x00", "a\x7f", strings.Repeat("k", 249)} {
+		var ke keyError
+		if err := db.Insert(ctx, "t", key, dbtest.Row("a")); !errors.As(err, &ke) {
+			t.Fatalf("want a key error for inserting %q, but got %v", key, err)
+		}
+		if _, err := db.Read(ctx, "t", key, nil); !errors.As(err, &ke) {
+			t.Fatalf("want a key error for reading %q, but got %v", key, err)
+		}
+		if err := db.Delete(ctx, "t", key); !errors.As(err, &ke) {
+			t.Fatalf("want a key error for deleting %q, but got %v", key, err)
+		}
+		if _, err := db.(ycsb.BatchDB).BatchRead(ctx, "t", []string{"k", key}, nil); !errors.As(err, &ke) {
+			t.Fatalf("want a key error for reading %q in a batch, but got %v", key, err)
+		}
+	}
+	if n := s.len(); n != 0 {
+		t.Fatalf("want nothing sent, but got %d keys", n)
+	}
+
+	// The connection is reused after the key errors.
+	key := strings.Repeat("k", 248)
+	if err := db.Insert(ctx, "t", key, dbtest.Row("a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Read(ctx, "t", key, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	for _, proto := range []string{"text", "binary"} {
+		ctx := context.Background()
+		s1, s2 := newStubServer(t), newStubServer(t)
+		defer s1.l.Close()
+		defer s2.l.Close()
+		db := newTestDB(t, proto, s1, s2)
+		defer db.Close()
+		bdb := db.(ycsb.BatchDB)
+
+		var keys []string
+		var values []map[string][]byte
+		for i := 0; i < 20; i++ {
+			key := fmt.Sprintf("user%d", i)
+			keys = append(keys, key)
+			values = append(values, dbtest.Row(key))
+		}
+		if err := bdb.BatchInsert(ctx, "t", keys, values); err != nil {
+			t.Fatal(err)
+		}
+		// The keys are placed on both servers.
+		if s1.len() == 0 || s2.len() == 0 {
+			t.Fatalf("want the keys on both servers, but got %d and %d", s1.len(), s2.len())
+		}
+
+		if err := bdb.BatchUpdate(ctx, "t", keys[:2], []map[string][]byte{{"field0": []byte("x")}, {"field0": []byte("y")}}); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := bdb.BatchRead(ctx, "t", keys, []string{"field0"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, row := range rows {
+			want := keys[i]
+			if i < 2 {
+				want = []string{"x", "y"}[i]
+			}
+			if string(row["field0"]) != want {
+				t.Fatalf("%s: want row %d %s, but got %s", proto, i, want, row["field0"])
+			}
+		}
+
+		if err = bdb.BatchDelete(ctx, "t", keys[1:]); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = bdb.BatchRead(ctx, "t", keys, nil); err != ErrCacheMiss {
+			t.Fatalf("want the cache miss for reading the deleted keys, but got %v", err)
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	servers := []string{"10.0.0.1:11211", "10.0.0.2:11211", "10.0.0.3:11211"}
+	r := newRing(servers)
+	added := newRing(append(servers, "10.0.0.4:11211"))
+
+	const n = 10000
+	counts := make([]int, len(servers))
+	moved := 0
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("usertable:user%d", i)
+		s := r.pick(key)
+		counts[s]++
+		if added.pick(key) != s {
+			moved++
+		}
+	}
+
+	for i, count := range counts {
+		if count < n/len(servers)/2 {
+			t.Fatalf("want about %d keys on server %d, but got %d", n/len(servers), i, count)
+		}
+	}
+	// Only the keys of the new server are moved.
+	if moved > n/2 {
+		t.Fatalf("want about %d moved keys, but got %d", n/4, moved)
+	}
+}
+
+func TestBinaryKeyFormat(t *testing.T) {
+	p := properties.NewProperties()
+	p.Set(prop.KeyFormat, "binary")
+	p.Set(memcachedProtocol, "text")
+	if _, err := (memcachedCreator{}).Create(p); err == nil {
+		t.Fatal("want an error for the binary keys of the text protocol")
+	}
+	// The binary protocol takes any byte in the keys.
+	p.Set(memcachedProtocol, "binary")
+	if _, err := (memcachedCreator{}).Create(p); err != nil {
+		t.Fatal(err)
+	}
+}
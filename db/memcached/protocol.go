@@ -0,0 +1,325 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memcached
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrCacheMiss is returned if the key is not found.
+var ErrCacheMiss = errors.New("memcached: cache miss")
+
+// protocol runs the commands on a connection. The connection is only reused after the
+// commands succeed or return ErrCacheMiss or a keyError, which is returned before
+// sending anything.
+type protocol interface {
+	// get returns the values of the found keys.
+	get(rw *bufio.ReadWriter, keys []string) (map[string][]byte, error)
+	set(rw *bufio.ReadWriter, key string, value []byte, expiration uint32) error
+	delete(rw *bufio.ReadWriter, key string) error
+}
+
+// protocolError is the error replied by the server.
+type protocolError struct {
+	msg string
+}
+
+func (e protocolError) Error() string {
+	return "memcached: " + e.msg
+}
+
+// maxKeyLen is the max length of the memcached keys.
+const maxKeyLen = 250
+
+// keyError is returned for the keys which the text protocol can't send.
+type keyError struct {
+	key string
+	msg string
+}
+
+func (e keyError) Error() string {
+	return fmt.Sprintf("memcached: invalid key %q, %s", e.key, e.msg)
+}
+
+// checkKey checks the key of the text protocol, which separates the keys with spaces
+// and the commands with lines.
+func checkKey(key string) error {
+	if len(key) == 0 || len(key) > maxKeyLen {
+		return keyError{key: key, msg: fmt.Sprintf("the length %d is out of [1, %d]", len(key), maxKeyLen)}
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return keyError{key: key, msg: "whitespace and control characters are not allowed"}
+		}
+	}
+	return nil
+}
+
+// textProtocol is the memcached text protocol.
+type textProtocol struct{}
+
+func readLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(line, []byte("\r\n")), nil
+}
+
+// replyError returns the error of the error replies.
+func replyError(line []byte) error {
+	for _, prefix := range []string{"ERROR", "CLIENT_ERROR", "SERVER_ERROR"} {
+		if bytes.HasPrefix(line, []byte(prefix)) {
+			return protocolError{msg: string(line)}
+		}
+	}
+	return fmt.Errorf("memcached: unexpected reply %q", line)
+}
+
+func (textProtocol) get(rw *bufio.ReadWriter, keys []string) (map[string][]byte, error) {
+	for _, key := range keys {
+		if err := checkKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	rw.WriteString("get")
+	for _, key := range keys {
+		rw.WriteString(" ")
+		rw.WriteString(key)
+	}
+	rw.WriteString("\r\n")
+	if err := rw.Flush(); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]byte, len(keys))
+	for {
+		line, err := readLine(rw.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(line, []byte("END")) {
+			return values, nil
+		}
+
+		// VALUE <key> <flags> <bytes>
+		parts := bytes.Fields(line)
+		if len(parts) != 4 || !bytes.Equal(parts[0], []byte("VALUE")) {
+			return nil, replyError(line)
+		}
+		size, err := strconv.Atoi(string(parts[3]))
+		if err != nil {
+			return nil, fmt.Errorf("memcached: unexpected reply %q", line)
+		}
+
+		value := make([]byte, size+2)
+		if _, err = io.ReadFull(rw, value); err != nil {
+			return nil, err
+		}
+		values[string(parts[1])] = value[:size]
+	}
+}
+
+func (textProtocol) set(rw *bufio.ReadWriter, key string, value []byte, expiration uint32) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(rw, "set %s 0 %d %d\r\n", key, expiration, len(value))
+	rw.Write(value)
+	rw.WriteString("\r\n")
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	line, err := readLine(rw.Reader)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(line, []byte("STORED")) {
+		return replyError(line)
+	}
+	return nil
+}
+
+func (textProtocol) delete(rw *bufio.ReadWriter, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(rw, "delete %s\r\n", key)
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	line, err := readLine(rw.Reader)
+	if err != nil {
+		return err
+	}
+	switch {
+	case bytes.Equal(line, []byte("DELETED")):
+		return nil
+	case bytes.Equal(line, []byte("NOT_FOUND")):
+		return ErrCacheMiss
+	default:
+		return replyError(line)
+	}
+}
+
+// binaryProtocol is the memcached binary protocol.
+type binaryProtocol struct{}
+
+const (
+	binaryRequest  = 0x80
+	binaryResponse = 0x81
+
+	opSet   = 0x01
+	opDel   = 0x04
+	opNoop  = 0x0a
+	opGetKQ = 0x0d
+
+	statusOK          = 0x0000
+	statusKeyNotFound = 0x0001
+
+	headerLen = 24
+)
+
+type header struct {
+	magic    byte
+	opcode   byte
+	keyLen   uint16
+	extraLen uint8
+	status   uint16
+	bodyLen  uint32
+}
+
+func writeRequest(w io.Writer, opcode byte, extras []byte, key string, value []byte) {
+	var h [headerLen]byte
+	h[0] = binaryRequest
+	h[1] = opcode
+	binary.BigEndian.PutUint16(h[2:], uint16(len(key)))
+	h[4] = byte(len(extras))
+	binary.BigEndian.PutUint32(h[8:], uint32(len(extras)+len(key)+len(value)))
+	w.Write(h[:])
+	w.Write(extras)
+	io.WriteString(w, key)
+	w.Write(value)
+}
+
+// readResponse reads a response, and returns its header, key and value.
+func readResponse(r io.Reader) (header, []byte, []byte, error) {
+	var b [headerLen]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return header{}, nil, nil, err
+	}
+
+	h := header{
+		magic:    b[0],
+		opcode:   b[1],
+		keyLen:   binary.BigEndian.Uint16(b[2:]),
+		extraLen: b[4],
+		status:   binary.BigEndian.Uint16(b[6:]),
+		bodyLen:  binary.BigEndian.Uint32(b[8:]),
+	}
+	if h.magic != binaryResponse || int(h.extraLen)+int(h.keyLen) > int(h.bodyLen) {
+		return header{}, nil, nil, fmt.Errorf("memcached: unexpected response header %x", b)
+	}
+
+	body := make([]byte, h.bodyLen)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return header{}, nil, nil, err
+	}
+	key := body[h.extraLen : int(h.extraLen)+int(h.keyLen)]
+	return h, key, body[int(h.extraLen)+int(h.keyLen):], nil
+}
+
+func statusError(h header, value []byte) error {
+	if h.status == statusKeyNotFound {
+		return ErrCacheMiss
+	}
+	return protocolError{msg: fmt.Sprintf("status %#x %s", h.status, value)}
+}
+
+// get sends the quiet GETKQ of every key and a NOOP, and the server only replies the
+// found keys before the NOOP.
+func (binaryProtocol) get(rw *bufio.ReadWriter, keys []string) (map[string][]byte, error) {
+	for _, key := range keys {
+		writeRequest(rw, opGetKQ, nil, key, nil)
+	}
+	writeRequest(rw, opNoop, nil, "", nil)
+	if err := rw.Flush(); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]byte, len(keys))
+	var err error
+	for {
+		h, key, value, rerr := readResponse(rw)
+		if rerr != nil {
+			return nil, rerr
+		}
+		if h.opcode == opNoop {
+			return values, err
+		}
+		if h.status != statusOK {
+			if h.status != statusKeyNotFound && err == nil {
+				err = statusError(h, value)
+			}
+			continue
+		}
+		values[string(key)] = value
+	}
+}
+
+func (binaryProtocol) set(rw *bufio.ReadWriter, key string, value []byte, expiration uint32) error {
+	// The extras are the flags and the expiration.
+	var extras [8]byte
+	binary.BigEndian.PutUint32(extras[4:], expiration)
+	writeRequest(rw, opSet, extras[:], key, value)
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	h, _, body, err := readResponse(rw)
+	if err != nil {
+		return err
+	}
+	if h.status != statusOK {
+		return statusError(h, body)
+	}
+	return nil
+}
+
+func (binaryProtocol) delete(rw *bufio.ReadWriter, key string) error {
+	writeRequest(rw, opDel, nil, key, nil)
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	h, _, body, err := readResponse(rw)
+	if err != nil {
+		return err
+	}
+	if h.status != statusOK {
+		return statusError(h, body)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memcached
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// pointsPerServer is the number of the points of every server on the ring, 4 points
+// for every md5 sum like ketama.
+const pointsPerServer = 160
+
+type point struct {
+	hash   uint32
+	server int
+}
+
+// ring is the ketama consistent hashing ring, so adding or removing a server only moves
+// the keys of its own points, and the keys are placed like the ketama clients and twemproxy.
+type ring struct {
+	points []point
+}
+
+func newRing(servers []string) *ring {
+	r := &ring{points: make([]point, 0, len(servers)*pointsPerServer)}
+	for i, server := range servers {
+		for j := 0; j < pointsPerServer/4; j++ {
+			sum := md5.Sum([]byte(fmt.Sprintf("%s-%d", server, j)))
+			for k := 0; k < 4; k++ {
+				r.points = append(r.points, point{
+					hash:   binary.LittleEndian.Uint32(sum[k*4:]),
+					server: i,
+				})
+			}
+		}
+	}
+
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i].hash < r.points[j].hash
+	})
+	return r
+}
+
+// pick returns the index of the server of the key, which is the first point at or after
+// the key hash on the ring.
+func (r *ring) pick(key string) int {
+	sum := md5.Sum([]byte(key))
+	h := binary.LittleEndian.Uint32(sum[:4])
+
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].server
+}
@@ -0,0 +1,353 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// properties
+const (
+	memoryShards           = "memory.shards"
+	memoryShardsDefault    = 16
+	memoryLatency          = "memory.latency"
+	memoryLatencyDefault   = time.Duration(0)
+	memoryErrorRate        = "memory.errorrate"
+	memoryErrorRateDefault = float64(0)
+)
+
+// ErrInjected is returned by the operations failed by memory.errorrate.
+var ErrInjected = errors.New("memory: injected error")
+
+type shard struct {
+	sync.RWMutex
+	rows *skiplist
+}
+
+// table is split into shards by the key hash, every shard keeps its keys in order.
+type table struct {
+	shards []*shard
+}
+
+func newTable(shardCount int) *table {
+	t := &table{shards: make([]*shard, shardCount)}
+	for i := range t.shards {
+		t.shards[i] = &shard{rows: newSkiplist(int64(i))}
+	}
+	return t
+}
+
+func (t *table) shard(key string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return t.shards[h.Sum32()%uint32(len(t.shards))]
+}
+
+// memoryDB stores the records in memory, so the workloads and the client can be tested
+// without a database. The data is lost when the process exits.
+type memoryDB struct {
+	shardCount int
+	latency    time.Duration
+	errorRate  float64
+
+	mu     sync.RWMutex
+	tables map[string]*table
+}
+
+type memoryCreator struct{}
+
+func (memoryCreator) Create(p *properties.Properties) (ycsb.DB, error) {
+	db := &memoryDB{
+		shardCount: p.GetInt(memoryShards, memoryShardsDefault),
+		latency:    p.GetParsedDuration(memoryLatency, memoryLatencyDefault),
+		errorRate:  p.GetFloat64(memoryErrorRate, memoryErrorRateDefault),
+		tables:     make(map[string]*table),
+	}
+	if db.shardCount <= 0 {
+		return nil, fmt.Errorf("%s must be positive", memoryShards)
+	}
+	if db.errorRate < 0 || db.errorRate > 1 {
+		return nil, fmt.Errorf("%s must be in [0, 1]", memoryErrorRate)
+	}
+	return db, nil
+}
+
+func (db *memoryDB) Close() error {
+	return nil
+}
+
+func (db *memoryDB) InitThread(ctx context.Context, _ int, _ int) context.Context {
+	return ctx
+}
+
+func (db *memoryDB) CleanupThread(_ context.Context) {
+}
+
+func (db *memoryDB) table(name string) *table {
+	db.mu.RLock()
+	t, ok := db.tables[name]
+	db.mu.RUnlock()
+	if ok {
+		return t
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if t, ok = db.tables[name]; !ok {
+		t = newTable(db.shardCount)
+		db.tables[name] = t
+	}
+	return t
+}
+
+// inject sleeps memory.latency and fails the operation by memory.errorrate.
+func (db *memoryDB) inject(ctx context.Context) error {
+	if db.latency > 0 {
+		select {
+		case <-time.After(db.latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if db.errorRate > 0 && rand.Float64() < db.errorRate {
+		return ErrInjected
+	}
+	return nil
+}
+
+// project returns a copy of the row with the fields, or all the fields if fields is empty.
+// The values are shared, because the stored values are never changed.
+func project(row map[string][]byte, fields []string) map[string][]byte {
+	if len(fields) == 0 {
+		m := make(map[string][]byte, len(row))
+		for field, value := range row {
+			m[field] = value
+		}
+		return m
+	}
+
+	m := make(map[string][]byte, len(fields))
+	for _, field := range fields {
+		if value, ok := row[field]; ok {
+			m[field] = value
+		}
+	}
+	return m
+}
+
+// cloneValues copies the values, which the workload reuses after the operation.
+func cloneValues(values map[string][]byte) map[string][]byte {
+	m := make(map[string][]byte, len(values))
+	for field, value := range values {
+		m[field] = append([]byte(nil), value...)
+	}
+	return m
+}
+
+func (db *memoryDB) read(table string, key string, fields []string) (map[string][]byte, error) {
+	s := db.table(table).shard(key)
+	s.RLock()
+	defer s.RUnlock()
+
+	n := s.rows.get(key)
+	if n == nil {
+		return nil, fmt.Errorf("key %w: %s.%s", ycsb.ErrNotFound, table, key)
+	}
+	return project(n.row, fields), nil
+}
+
+func (db *memoryDB) update(table string, key string, values map[string][]byte) error {
+	s := db.table(table).shard(key)
+	s.Lock()
+	defer s.Unlock()
+
+	n := s.rows.get(key)
+	if n == nil {
+		return fmt.Errorf("key not found: %s.%s", table, key)
+	}
+	for field, value := range values {
+		n.row[field] = append([]byte(nil), value...)
+	}
+	return nil
+}
+
+func (db *memoryDB) insert(table string, key string, values map[string][]byte) {
+	row := cloneValues(values)
+	s := db.table(table).shard(key)
+	s.Lock()
+	s.rows.put(key, row)
+	s.Unlock()
+}
+
+func (db *memoryDB) delete(table string, key string) {
+	s := db.table(table).shard(key)
+	s.Lock()
+	s.rows.delete(key)
+	s.Unlock()
+}
+
+type kv struct {
+	key string
+	row map[string][]byte
+}
+
+// scan reads at most limit records in [startKey, endKey) in the key order, an empty
+// endKey means no end. Every shard reads the first limit records of its own, then they
+// are merged.
+func (db *memoryDB) scan(table string, startKey string, endKey string, limit int, fields []string) []map[string][]byte {
+	var kvs []kv
+	for _, s := range db.table(table).shards {
+		s.RLock()
+		n := s.rows.seek(startKey)
+		for i := 0; n != nil && i < limit && (len(endKey) == 0 || n.key < endKey); i++ {
+			kvs = append(kvs, kv{key: n.key, row: project(n.row, fields)})
+			n = n.next[0]
+		}
+		s.RUnlock()
+	}
+
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].key < kvs[j].key
+	})
+	if len(kvs) > limit {
+		kvs = kvs[:limit]
+	}
+
+	res := make([]map[string][]byte, 0, len(kvs))
+	for _, kv := range kvs {
+		res = append(res, kv.row)
+	}
+	return res
+}
+
+func (db *memoryDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	if err := db.inject(ctx); err != nil {
+		return nil, err
+	}
+	return db.read(table, key, fields)
+}
+
+func (db *memoryDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
+	if err := db.inject(ctx); err != nil {
+		return nil, err
+	}
+	return db.scan(table, startKey, "", count, fields), nil
+}
+
+func (db *memoryDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	return db.update(table, key, values)
+}
+
+func (db *memoryDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	db.insert(table, key, values)
+	return nil
+}
+
+func (db *memoryDB) Delete(ctx context.Context, table string, key string) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	db.delete(table, key)
+	return nil
+}
+
+func (db *memoryDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		db.insert(table, key, values[i])
+	}
+	return nil
+}
+
+func (db *memoryDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	if err := db.inject(ctx); err != nil {
+		return nil, err
+	}
+	rows := make([]map[string][]byte, 0, len(keys))
+	for _, key := range keys {
+		row, err := db.read(table, key, fields)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (db *memoryDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	for i, key := range keys {
+		if err := db.update(table, key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *memoryDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		db.delete(table, key)
+	}
+	return nil
+}
+
+func (db *memoryDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	if err := db.inject(ctx); err != nil {
+		return nil, err
+	}
+	return db.scan(table, startKey, endKey, limit, fields), nil
+}
+
+func (db *memoryDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	for _, s := range db.table(table).shards {
+		s.Lock()
+		for n := s.rows.seek(startKey); n != nil && n.key < endKey; {
+			next := n.next[0]
+			s.rows.delete(n.key)
+			n = next
+		}
+		s.Unlock()
+	}
+	return nil
+}
+
+func init() {
+	ycsb.RegisterDBCreator("memory", memoryCreator{})
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/dbtest"
+)
+
+func newTestDB(t *testing.T, s string) *memoryDB {
+	db, err := memoryCreator{}.Create(properties.MustLoadString(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.(*memoryDB)
+}
+
+func TestSkiplist(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := newSkiplist(1)
+	m := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("%d", r.Intn(2000))
+		if r.Intn(3) == 0 {
+			if s.delete(key) != m[key] {
+				t.Fatalf("delete %s, want existing %v", key, m[key])
+			}
+			delete(m, key)
+		} else {
+			if s.put(key, nil) == m[key] {
+				t.Fatalf("put %s, want existing %v", key, m[key])
+			}
+			m[key] = true
+		}
+	}
+
+	var want []string
+	for key := range m {
+		want = append(want, key)
+	}
+	sort.Strings(want)
+
+	var got []string
+	for n := s.seek(""); n != nil; n = n.next[0] {
+		got = append(got, n.key)
+	}
+	if !reflect.DeepEqual(want, got) || s.len != len(want) {
+		t.Fatalf("want %d keys in order, but got %d keys, len %d", len(want), len(got), s.len)
+	}
+}
+
+func TestCRUD(t *testing.T) {
+	dbtest.TestCRUD(t, newTestDB(t, ""))
+}
+
+func TestScan(t *testing.T) {
+	dbtest.TestScan(t, newTestDB(t, "memory.shards=7"))
+}
+
+func TestBatch(t *testing.T) {
+	dbtest.TestBatch(t, newTestDB(t, ""))
+}
+
+func TestInjectedError(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, "memory.errorrate=0.5\nmemory.latency=1ms")
+
+	failed := 0
+	for i := 0; i < 200; i++ {
+		if err := db.Insert(ctx, "t", "k", dbtest.Row("a")); err == ErrInjected {
+			failed++
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if failed < 50 || failed > 150 {
+		t.Fatalf("want about half of the inserts failed, but got %d", failed)
+	}
+
+	if _, err := (memoryCreator{}).Create(properties.MustLoadString("memory.errorrate=2")); err == nil {
+		t.Fatal("want an error for the invalid error rate")
+	}
+}
+
+func TestObject(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, "memory.shards=3")
+
+	data := []byte("0123456789")
+	for _, key := range []string{"a1", "a2", "a3", "b1"} {
+		if err := db.PutObject(ctx, "bucket", key, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The stored object must not change with the buffer of the workload.
+	data[0] = 'x'
+	if err := db.MultipartUpload(ctx, "bucket", "a4", []byte("abc"), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := db.GetObject(ctx, "bucket", "a1", 0, 0); err != nil || string(got) != "0123456789" {
+		t.Fatalf("get got %q, %v", got, err)
+	}
+	if got, err := db.GetObject(ctx, "bucket", "a1", 8, 4); err != nil || string(got) != "89" {
+		t.Fatalf("range get got %q, %v", got, err)
+	}
+	if size, err := db.HeadObject(ctx, "bucket", "a4"); err != nil || size != 3 {
+		t.Fatalf("head got %d, %v", size, err)
+	}
+
+	keys, err := db.ListObjects(ctx, "bucket", "a", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a1", "a2", "a3"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("want %q, but got %q", want, keys)
+	}
+
+	if err = db.DeleteObject(ctx, "bucket", "a1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.HeadObject(ctx, "bucket", "a1"); err == nil {
+		t.Fatal("want an error for the head of a deleted object")
+	}
+}
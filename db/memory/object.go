@@ -0,0 +1,114 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// objectField is the field of the record which keeps the content of an object. The
+// bucket is the table of the records.
+const objectField = "data"
+
+// listKeys returns at most limit keys with the prefix in the key order.
+func (db *memoryDB) listKeys(table string, prefix string, limit int) []string {
+	var keys []string
+	for _, s := range db.table(table).shards {
+		s.RLock()
+		n := s.rows.seek(prefix)
+		for i := 0; n != nil && i < limit && strings.HasPrefix(n.key, prefix); i++ {
+			keys = append(keys, n.key)
+			n = n.next[0]
+		}
+		s.RUnlock()
+	}
+
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
+
+// PutObject implements the ObjectDB PutObject interface.
+func (db *memoryDB) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	db.insert(bucket, key, map[string][]byte{objectField: data})
+	return nil
+}
+
+// GetObject implements the ObjectDB GetObject interface.
+func (db *memoryDB) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error) {
+	if err := db.inject(ctx); err != nil {
+		return nil, err
+	}
+	row, err := db.read(bucket, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := row[objectField]
+	if offset > int64(len(data)) {
+		return nil, fmt.Errorf("offset %d is out of the object %s.%s of %d bytes", offset, bucket, key, len(data))
+	}
+	end := int64(len(data))
+	if length > 0 && offset+length < end {
+		end = offset + length
+	}
+	return data[offset:end], nil
+}
+
+// HeadObject implements the ObjectDB HeadObject interface.
+func (db *memoryDB) HeadObject(ctx context.Context, bucket string, key string) (int64, error) {
+	if err := db.inject(ctx); err != nil {
+		return 0, err
+	}
+	row, err := db.read(bucket, key, nil)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(row[objectField])), nil
+}
+
+// ListObjects implements the ObjectDB ListObjects interface.
+func (db *memoryDB) ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error) {
+	if err := db.inject(ctx); err != nil {
+		return nil, err
+	}
+	return db.listKeys(bucket, prefix, count), nil
+}
+
+// DeleteObject implements the ObjectDB DeleteObject interface. Like S3, deleting a
+// missing object succeeds.
+func (db *memoryDB) DeleteObject(ctx context.Context, bucket string, key string) error {
+	if err := db.inject(ctx); err != nil {
+		return err
+	}
+	db.delete(bucket, key)
+	return nil
+}
+
+// MultipartUpload implements the ObjectDB MultipartUpload interface. The parts would be
+// joined in memory anyway, so the object is stored at once.
+func (db *memoryDB) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error {
+	if partSize <= 0 {
+		return fmt.Errorf("invalid part size %d", partSize)
+	}
+	return db.PutObject(ctx, bucket, key, data)
+}
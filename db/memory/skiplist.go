@@ -0,0 +1,125 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"math/rand"
+)
+
+const (
+	maxLevel    = 24
+	probability = 0.25
+)
+
+type node struct {
+	key  string
+	row  map[string][]byte
+	next []*node
+}
+
+// skiplist is an ordered map of the rows. It's not thread-safe, the shard locks it.
+type skiplist struct {
+	head  node
+	level int
+	len   int
+	r     *rand.Rand
+}
+
+func newSkiplist(seed int64) *skiplist {
+	return &skiplist{
+		head:  node{next: make([]*node, maxLevel)},
+		level: 1,
+		r:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *skiplist) randomLevel() int {
+	level := 1
+	for level < maxLevel && s.r.Float64() < probability {
+		level++
+	}
+	return level
+}
+
+// findGreaterOrEqual returns the first node whose key is not less than the key, and fills
+// prev with the last nodes before it on every level if prev is not nil.
+func (s *skiplist) findGreaterOrEqual(key string, prev []*node) *node {
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		if prev != nil {
+			prev[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+// seek returns the first node whose key is not less than the key, follow next[0] to
+// iterate the nodes in order.
+func (s *skiplist) seek(key string) *node {
+	return s.findGreaterOrEqual(key, nil)
+}
+
+func (s *skiplist) get(key string) *node {
+	if n := s.seek(key); n != nil && n.key == key {
+		return n
+	}
+	return nil
+}
+
+// put sets the row of the key, and returns false if the key exists and its row is replaced.
+func (s *skiplist) put(key string, row map[string][]byte) bool {
+	var prev [maxLevel]*node
+	n := s.findGreaterOrEqual(key, prev[:])
+	if n != nil && n.key == key {
+		n.row = row
+		return false
+	}
+
+	level := s.randomLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			prev[i] = &s.head
+		}
+		s.level = level
+	}
+
+	n = &node{key: key, row: row, next: make([]*node, level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = prev[i].next[i]
+		prev[i].next[i] = n
+	}
+	s.len++
+	return true
+}
+
+// delete removes the key, and returns false if the key doesn't exist.
+func (s *skiplist) delete(key string) bool {
+	var prev [maxLevel]*node
+	n := s.findGreaterOrEqual(key, prev[:])
+	if n == nil || n.key != key {
+		return false
+	}
+
+	for i := 0; i < len(n.next); i++ {
+		prev[i].next[i] = n.next[i]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.len--
+	return true
+}
@@ -123,6 +123,86 @@ func (db *minioDB) Delete(ctx context.Context, table string, key string) error {
 	return db.db.RemoveObject(table, key)
 }
 
+// PutObject implements the ObjectDB PutObject interface.
+func (db *minioDB) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	_, err := db.db.PutObjectWithContext(ctx, bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	return err
+}
+
+// GetObject implements the ObjectDB GetObject interface.
+func (db *minioDB) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error) {
+	opts := minio.GetObjectOptions{}
+	if offset > 0 || length > 0 {
+		end := int64(0)
+		if length > 0 {
+			end = offset + length - 1
+		}
+		if err := opts.SetRange(offset, end); err != nil {
+			return nil, err
+		}
+	}
+	obj, err := db.db.GetObjectWithContext(ctx, bucket, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	return ioutil.ReadAll(obj)
+}
+
+// HeadObject implements the ObjectDB HeadObject interface.
+func (db *minioDB) HeadObject(ctx context.Context, bucket string, key string) (int64, error) {
+	info, err := db.db.StatObject(bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return info.Size, nil
+}
+
+// ListObjects implements the ObjectDB ListObjects interface.
+func (db *minioDB) ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error) {
+	core := minio.Core{Client: db.db}
+	res, err := core.ListObjectsV2(bucket, prefix, "", false, "", count, "")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(res.Contents))
+	for _, obj := range res.Contents {
+		keys = append(keys, obj.Key)
+	}
+	return keys, nil
+}
+
+// DeleteObject implements the ObjectDB DeleteObject interface.
+func (db *minioDB) DeleteObject(ctx context.Context, bucket string, key string) error {
+	return db.db.RemoveObject(bucket, key)
+}
+
+// MultipartUpload implements the ObjectDB MultipartUpload interface.
+func (db *minioDB) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error {
+	core := minio.Core{Client: db.db}
+	uploadID, err := core.NewMultipartUpload(bucket, key, minio.PutObjectOptions{})
+	if err != nil {
+		return err
+	}
+
+	var parts []minio.CompletePart
+	for i := int64(0); i*partSize < int64(len(data)); i++ {
+		end := (i + 1) * partSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		part, err := core.PutObjectPart(bucket, key, uploadID, int(i+1), bytes.NewReader(data[i*partSize:end]), end-i*partSize, "", "", nil)
+		if err != nil {
+			core.AbortMultipartUpload(bucket, key, uploadID)
+			return err
+		}
+		parts = append(parts, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+
+	_, err = core.CompleteMultipartUpload(bucket, key, uploadID, parts)
+	return err
+}
+
 func init() {
 	ycsb.RegisterDBCreator("minio", minioCreator{})
 }
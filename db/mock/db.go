@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
 	"github.com/pingcap/go-ycsb/pkg/ycsb"
+	"math/rand"
 	"time"
 	"net/http"
 	"io/ioutil"
@@ -43,6 +46,7 @@ type mockOptions struct {
 	Port       string
 	DataLength uint64
 	Type       string
+	ValueGen   util.ValueGenerator
 }
 
 type mockClient struct {
@@ -52,6 +56,7 @@ type mockClient struct {
 	pool  string
 	port  string
 	mType string
+	vGen  util.ValueGenerator
 }
 
 type mockState struct {
@@ -61,7 +66,10 @@ type mockState struct {
 }
 
 func (r mockCreator) Create(p *properties.Properties) (ycsb.DB, error) {
-	opts := getOptions(p)
+	opts, err := getOptions(p)
+	if err != nil {
+		return nil, err
+	}
 	c := &mockClient{
 		p:     p,
 		l:     opts.DataLength,
@@ -69,19 +77,27 @@ func (r mockCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 		pool:  opts.Pool,
 		port:  opts.Port,
 		mType: opts.Type,
+		vGen:  opts.ValueGen,
 	}
 	newMockServer(opts.Path, opts.Port)
 	return c, nil
 }
 
-func getOptions(p *properties.Properties) mockOptions {
+func getOptions(p *properties.Properties) (mockOptions, error) {
 	path := p.GetString(cephPath, "/etc/ceph.conf")
 	pool := p.GetString(poolName, "rabbit")
 	port := p.GetString(mockPort, "80")
 	mType := p.GetString(mockType, "0")
 	length, err := humanize.ParseBytes(p.GetString(mockLength, "4KiB"))
 	if err != nil {
-		panic(err)
+		return mockOptions{}, err
+	}
+	var valueGen util.ValueGenerator
+	if _, ok := p.Get(prop.ValueGenerator); ok {
+		valueGen, err = util.NewValueGenerator(p)
+		if err != nil {
+			return mockOptions{}, err
+		}
 	}
 	return mockOptions{
 		Path:       path,
@@ -89,7 +105,8 @@ func getOptions(p *properties.Properties) mockOptions {
 		Port:       port,
 		DataLength: length,
 		Type:       mType,
-	}
+		ValueGen:   valueGen,
+	}, nil
 }
 
 func newMockServer(path string, port string) {
@@ -119,8 +136,12 @@ func (r *mockClient) Close() error {
 // The Returned context will be passed to the following usage.
 func (r *mockClient) InitThread(ctx context.Context, threadID int, threadCount int) context.Context {
 	mockData4K := make([]byte, r.l)
-	for i := 0; i < len(mockData4K); i++ {
-		mockData4K[i] = uint8(i % 255)
+	if r.vGen != nil {
+		r.vGen.Fill(rand.New(rand.NewSource(time.Now().UnixNano())), mockData4K)
+	} else {
+		for i := 0; i < len(mockData4K); i++ {
+			mockData4K[i] = uint8(i % 255)
+		}
 	}
 	state := &mockState{
 		data: mockData4K,
@@ -168,7 +189,11 @@ func (r *mockClient) Update(ctx context.Context, table string, key string, value
 // values: A map of field/value pairs to insert in the record.
 func (r *mockClient) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
 	state := ctx.Value(stateKey).(*mockState)
-	req, err := http.NewRequest(http.MethodPut, "http://127.0.0.1:"+r.port, bytes.NewReader(state.data))
+	return r.put(key, state.data)
+}
+
+func (r *mockClient) put(key string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, "http://127.0.0.1:"+r.port, bytes.NewReader(data))
 	if err != nil {
 		panic(err)
 	}
@@ -198,6 +223,37 @@ func (r *mockClient) Delete(ctx context.Context, table string, key string) error
 	return nil
 }
 
+// PutObject implements the ObjectDB PutObject interface.
+func (r *mockClient) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	return r.put(key, data)
+}
+
+// GetObject implements the ObjectDB GetObject interface.
+func (r *mockClient) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error) {
+	return nil, nil
+}
+
+// HeadObject implements the ObjectDB HeadObject interface.
+func (r *mockClient) HeadObject(ctx context.Context, bucket string, key string) (int64, error) {
+	return 0, nil
+}
+
+// ListObjects implements the ObjectDB ListObjects interface.
+func (r *mockClient) ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error) {
+	return nil, nil
+}
+
+// DeleteObject implements the ObjectDB DeleteObject interface.
+func (r *mockClient) DeleteObject(ctx context.Context, bucket string, key string) error {
+	return nil
+}
+
+// MultipartUpload implements the ObjectDB MultipartUpload interface. The mock server
+// has no multipart API, so the whole object is sent in one request.
+func (r *mockClient) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error {
+	return r.put(key, data)
+}
+
 func init() {
 	ycsb.RegisterDBCreator("mock", mockCreator{})
 }
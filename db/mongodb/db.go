@@ -119,6 +119,69 @@ func (m *mongoDB) Delete(ctx context.Context, table string, key string) error {
 	return nil
 }
 
+// CreateIndex creates an ascending index on the field.
+func (m *mongoDB) CreateIndex(ctx context.Context, table string, field string) error {
+	if _, err := m.coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.M{field: 1}}); err != nil {
+		return fmt.Errorf("CreateIndex error: %s", err.Error())
+	}
+	return nil
+}
+
+func (m *mongoDB) find(ctx context.Context, filter bson.M, opt *options.FindOptions) ([]map[string][]byte, error) {
+	cursor, err := m.coll.Find(ctx, filter, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	var docs []map[string][]byte
+	for cursor.Next(ctx) {
+		var doc map[string][]byte
+		if err := cursor.Decode(&doc); err != nil {
+			return docs, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, cursor.Err()
+}
+
+// Query documents by the field value.
+func (m *mongoDB) Query(ctx context.Context, table string, field string, value []byte, fields []string) ([]map[string][]byte, error) {
+	projection := map[string]bool{"_id": false}
+	for _, field := range fields {
+		projection[field] = true
+	}
+	opt := &options.FindOptions{Projection: projection}
+	docs, err := m.find(ctx, bson.M{field: value}, opt)
+	if err != nil {
+		return docs, fmt.Errorf("Query error: %s", err.Error())
+	}
+	return docs, nil
+}
+
+// RangeQuery documents ordered by the field value.
+func (m *mongoDB) RangeQuery(ctx context.Context, table string, field string, start []byte, count int, fields []string) ([]map[string][]byte, error) {
+	projection := map[string]bool{"_id": false}
+	for _, field := range fields {
+		projection[field] = true
+	}
+	limit := int64(count)
+	opt := &options.FindOptions{Projection: projection, Sort: bson.M{field: 1}, Limit: &limit}
+	docs, err := m.find(ctx, bson.M{field: bson.M{"$gte": start}}, opt)
+	if err != nil {
+		return docs, fmt.Errorf("RangeQuery error: %s", err.Error())
+	}
+	return docs, nil
+}
+
+// Count documents whose field value is in the range.
+func (m *mongoDB) Count(ctx context.Context, table string, field string, start []byte, end []byte) (int64, error) {
+	n, err := m.coll.CountDocuments(ctx, bson.M{field: bson.M{"$gte": start, "$lte": end}})
+	if err != nil {
+		return 0, fmt.Errorf("Count error: %s", err.Error())
+	}
+	return n, nil
+}
+
 type mongodbCreator struct {
 }
 
@@ -21,13 +21,16 @@ import (
 	"fmt"
 	"github.com/pingcap/go-ycsb/pkg/prop"
 	"github.com/pingcap/go-ycsb/pkg/util"
+	"io"
 	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	// mysql package
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/magiconair/properties"
 	"github.com/pingcap/go-ycsb/pkg/ycsb"
 )
@@ -42,7 +45,8 @@ const (
 	mysqlForceIndex = "mysql.force_index"
 	mysqlTrans      = "mysql.transaction"
 	mysqlTable      = "mysql.table"
-	// TODO: support batch and auto commit
+	mysqlBulkLoad   = "mysql.bulk_load"
+	// TODO: support auto commit
 )
 
 type Object struct {
@@ -65,6 +69,8 @@ type mysqlDB struct {
 	trans             bool
 	randomKey         bool
 	table             string
+	// bulkLoad inserts the batches by LOAD DATA LOCAL INFILE instead of a multi-row INSERT.
+	bulkLoad bool
 
 	bufPool *util.BufPool
 }
@@ -99,6 +105,11 @@ func (c mysqlCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	d := new(mysqlDB)
 	d.p = p
 
+	// The binary keys may be invalid strings or contain NUL for the VARCHAR key.
+	if strings.ToLower(p.GetString(prop.KeyFormat, prop.KeyFormatDefault)) == "binary" {
+		return nil, fmt.Errorf("mysql doesn't support keyformat=binary")
+	}
+
 	host := p.GetString(mysqlHost, "127.0.0.1")
 	port := p.GetInt(mysqlPort, 3306)
 	user := p.GetString(mysqlUser, "root")
@@ -126,6 +137,7 @@ func (c mysqlCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	db.SetMaxOpenConns(threadCount * 2)
 
 	d.verbose = p.GetBool(prop.Verbose, prop.VerboseDefault)
+	d.bulkLoad = p.GetBool(mysqlBulkLoad, false)
 	if p.GetBool(mysqlForceIndex, true) {
 		d.forceIndexKeyword = "FORCE INDEX(`PRIMARY`)"
 	}
@@ -149,15 +161,14 @@ func (db *mysqlDB) createTable() error {
 		}
 	}
 
-	fieldCount := db.p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
-	fieldLength := db.p.GetInt64(prop.FieldLength, prop.FieldLengthDefault)
+	schema := util.LoadSchema(db.p)
 
 	buf := new(bytes.Buffer)
 	s := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (YCSB_KEY VARCHAR(64) PRIMARY KEY", tableName)
 	buf.WriteString(s)
 
-	for i := int64(0); i < fieldCount; i++ {
-		buf.WriteString(fmt.Sprintf(", FIELD%d VARCHAR(%d)", i, fieldLength))
+	for _, f := range schema.Fields {
+		buf.WriteString(fmt.Sprintf(", %s %s", f.Name, columnType(f)))
 	}
 
 	buf.WriteString(");")
@@ -166,10 +177,49 @@ func (db *mysqlDB) createTable() error {
 		fmt.Println(buf.String())
 	}
 
-	_, err := db.db.Exec(buf.String())
+	if _, err := db.db.Exec(buf.String()); err != nil {
+		return err
+	}
+	return db.createMetadataTable(schema)
+}
+
+// createMetadataTable creates the table of the load metadata, whose field keeps the
+// metadata in JSON.
+func (db *mysqlDB) createMetadataTable(schema *util.Schema) error {
+	tableName := util.MetadataTable(db.p)
+	f := schema.MetadataField()
+	if len(tableName) == 0 || f == nil {
+		return nil
+	}
+
+	if db.p.GetBool(prop.DropData, prop.DropDataDefault) && !db.p.GetBool(prop.DoTransactions, true) {
+		if _, err := db.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)); err != nil {
+			return err
+		}
+	}
+
+	colType := "TEXT"
+	if f.Type == util.FieldTypeBytes {
+		colType = "BLOB"
+	}
+	_, err := db.db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (YCSB_KEY VARCHAR(64) PRIMARY KEY, %s %s);", tableName, f.Name, colType))
 	return err
 }
 
+// columnType returns the column type of the field in the schema.
+func columnType(f util.SchemaField) string {
+	switch f.Type {
+	case util.FieldTypeBytes:
+		return fmt.Sprintf("VARBINARY(%d)", f.Length)
+	case util.FieldTypeInt:
+		return "BIGINT"
+	case util.FieldTypeTimestamp:
+		return "DATETIME(6)"
+	default:
+		return fmt.Sprintf("VARCHAR(%d)", f.Length)
+	}
+}
+
 func (db *mysqlDB) Close() error {
 	if db.db == nil {
 		return nil
@@ -298,6 +348,58 @@ func (db *mysqlDB) Read(ctx context.Context, table string, key string, fields []
 	return rows[0], nil
 }
 
+// placeholders returns n comma-separated placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// BatchRead reads the records by the queries of the keys, each with at most maxParams keys.
+// Like Read, the records of the missing keys are nil.
+func (db *mysqlDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	byKey := make(map[string]map[string][]byte, len(keys))
+	for start := 0; start < len(keys); start += maxParams {
+		end := start + maxParams
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		var query string
+		if len(fields) == 0 {
+			query = fmt.Sprintf(`SELECT * FROM %s %s WHERE YCSB_KEY IN (%s)`, table, db.forceIndexKeyword, placeholders(end-start))
+		} else {
+			query = fmt.Sprintf(`SELECT YCSB_KEY, %s FROM %s %s WHERE YCSB_KEY IN (%s)`, strings.Join(fields, ","), table, db.forceIndexKeyword, placeholders(end-start))
+		}
+
+		rows, err := db.queryRows(ctx, query, end-start, keyArgs(keys[start:end])...)
+		db.clearCacheIfFailed(ctx, query, err)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			byKey[string(row["YCSB_KEY"])] = row
+			if len(fields) > 0 {
+				delete(row, "YCSB_KEY")
+			}
+		}
+	}
+
+	res := make([]map[string][]byte, len(keys))
+	for i, key := range keys {
+		res[i] = byKey[key]
+	}
+	return res, nil
+}
+
+// keyArgs returns the keys as the arguments of a statement.
+func keyArgs(keys []string) []interface{} {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return args
+}
+
 func (db *mysqlDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
 	var query string
 	if len(fields) == 0 {
@@ -312,6 +414,20 @@ func (db *mysqlDB) Scan(ctx context.Context, table string, startKey string, coun
 	return rows, err
 }
 
+func (db *mysqlDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s %s WHERE YCSB_KEY >= ? AND YCSB_KEY < ? LIMIT ?`, table, db.forceIndexKeyword)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s %s WHERE YCSB_KEY >= ? AND YCSB_KEY < ? LIMIT ?`, strings.Join(fields, ","), table, db.forceIndexKeyword)
+	}
+
+	rows, err := db.queryRows(ctx, query, limit, startKey, endKey, limit)
+	db.clearCacheIfFailed(ctx, query, err)
+
+	return rows, err
+}
+
 func (db *mysqlDB) execQuery(ctx context.Context, query string, args ...interface{}) error {
 	if db.verbose {
 		fmt.Printf("%s %v\n", query, args)
@@ -350,7 +466,7 @@ func (db *mysqlDB) execQuery(ctx context.Context, query string, args ...interfac
 	}
 }
 
-func (db *mysqlDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+func (db *mysqlDB) updateQuery(table string, key string, values map[string][]byte) (string, []interface{}) {
 	buf := db.bufPool.Get()
 	defer db.bufPool.Put(buf)
 
@@ -375,22 +491,130 @@ func (db *mysqlDB) Update(ctx context.Context, table string, key string, values
 
 	args = append(args, key)
 
-	return db.execQuery(ctx, buf.String(), args...)
+	return buf.String(), args
 }
 
-func (db *mysqlDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) (err error) {
-	var ibucketname, iname, iversion, ilocation, ipool, iownerId, isize, iobjectId, ilastModifiedTime, ietag, icontentType, icustomattributes, iacl, ioullVersion, ideleteMarker, isseType, iencryptionKey, iinitializationVector, itype, istorageClass, value string
-	args := make([]interface{}, 0, 1+len(values))
+func (db *mysqlDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	query, args := db.updateQuery(table, key, values)
+	return db.execQuery(ctx, query, args...)
+}
+
+// BatchUpdate updates the records one by one in a transaction.
+func (db *mysqlDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	state := ctx.Value(stateKey).(*mysqlState)
+	tx, err := state.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		query, args := db.updateQuery(table, key, values[i])
+		if db.verbose {
+			fmt.Printf("%s %v\n", query, args)
+		}
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rowKey returns the key of the inserted row, which has a random suffix with randomkey.
+func (db *mysqlDB) rowKey(key string) string {
 	if db.randomKey {
-		value = key + "_" + strconv.FormatInt(rand.Int63(), 10)
-	} else {
-		value = key
+		return key + "_" + strconv.FormatInt(rand.Int63(), 10)
 	}
-	args = append(args, value)
+	return key
+}
 
+// insertQuery builds the INSERT of rowCount rows of the fields.
+func (db *mysqlDB) insertQuery(table string, fields []string, rowCount int) string {
 	buf := db.bufPool.Get()
 	defer db.bufPool.Put(buf)
 
+	buf.WriteString("INSERT IGNORE INTO ")
+	buf.WriteString(table)
+	buf.WriteString(" (YCSB_KEY")
+
+	for _, field := range fields {
+		buf.WriteString(" ,")
+		buf.WriteString(field)
+	}
+	buf.WriteString(") VALUES ")
+
+	for i := 0; i < rowCount; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("(?")
+		for j := 0; j < len(fields); j++ {
+			buf.WriteString(" ,?")
+		}
+		buf.WriteByte(')')
+	}
+
+	return buf.String()
+}
+
+// maxParams is the max number of the parameters of a statement in MySQL.
+const maxParams = 65535
+
+// insertFields returns the sorted fields of all the values, and the records of a batch
+// can have different fields.
+func insertFields(values ...map[string][]byte) []string {
+	seen := make(map[string]struct{})
+	var fields []string
+	for _, m := range values {
+		for field := range m {
+			if _, ok := seen[field]; !ok {
+				seen[field] = struct{}{}
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// insertArgs appends the key and the values of the fields, and the missing fields are NULL.
+func insertArgs(args []interface{}, key string, fields []string, values map[string][]byte) []interface{} {
+	args = append(args, key)
+	for _, field := range fields {
+		if v, ok := values[field]; ok {
+			args = append(args, v)
+		} else {
+			args = append(args, nil)
+		}
+	}
+	return args
+}
+
+// batchInsert inserts the records by the multi-row INSERTs, each with at most
+// maxParams parameters.
+func (db *mysqlDB) batchInsert(ctx context.Context, table string, keys []string, fields []string, values []map[string][]byte) error {
+	rowCount := maxParams / (1 + len(fields))
+	for start := 0; start < len(keys); start += rowCount {
+		end := start + rowCount
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		args := make([]interface{}, 0, (end-start)*(1+len(fields)))
+		for i := start; i < end; i++ {
+			args = insertArgs(args, db.rowKey(keys[i]), fields, values[i])
+		}
+		if err := db.execQuery(ctx, db.insertQuery(table, fields, end-start), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *mysqlDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) (err error) {
+	var ibucketname, iname, iversion, ilocation, ipool, iownerId, isize, iobjectId, ilastModifiedTime, ietag, icontentType, icustomattributes, iacl, ioullVersion, ideleteMarker, isseType, iencryptionKey, iinitializationVector, itype, istorageClass string
+
 	if db.table == "objects" { //If you specify a table name and the table name is objects, execute the method
 		bucketName := "test_for_ycsb"
 		name := strconv.FormatInt(time.Now().UnixNano(), 10) + "_" + strconv.FormatInt(rand.Int63(), 10)
@@ -452,25 +676,84 @@ func (db *mysqlDB) Insert(ctx context.Context, table string, key string, values
 		err = db.execQuery(ctx, insert_sql, bucketName, name, version, location, pool, ownerid, size, objectid, lastmodifiedtime,
 			etag, contenttype, customattributes, acl, nullversion, deletemarker, ssetype, encryptionkey, initializationvector, typetype, storageclass, createtime)
 	} else {
-		buf.WriteString("INSERT IGNORE INTO ")
-		buf.WriteString(table)
-		buf.WriteString(" (YCSB_KEY")
-
-		pairs := util.NewFieldPairs(values)
-		for _, p := range pairs {
-			args = append(args, p.Value)
-			buf.WriteString(" ,")
-			buf.WriteString(p.Field)
+		fields := insertFields(values)
+		args := insertArgs(make([]interface{}, 0, 1+len(fields)), db.rowKey(key), fields, values)
+		err = db.execQuery(ctx, db.insertQuery(table, fields, 1), args...)
+	}
+	return err
+}
+
+// BatchInsert inserts the records by the multi-row INSERTs, or with mysql.bulk_load, by LOAD
+// DATA LOCAL INFILE. The fields of the records are the fields of all the records.
+func (db *mysqlDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	if db.table == "objects" {
+		for i, key := range keys {
+			if err := db.Insert(ctx, table, key, values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fields := insertFields(values...)
+	if db.bulkLoad {
+		return db.loadData(ctx, table, keys, fields, values)
+	}
+	return db.batchInsert(ctx, table, keys, fields, values)
+}
+
+// loadDataID makes the names of the LOAD DATA readers unique.
+var loadDataID int64
+
+// escapeLoadData appends the value escaped for the default FIELDS and LINES options of
+// LOAD DATA.
+func escapeLoadData(buf *bytes.Buffer, v []byte) {
+	for _, c := range v {
+		switch c {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case 0:
+			buf.WriteString(`\0`)
+		default:
+			buf.WriteByte(c)
 		}
-		buf.WriteString(") VALUES (?")
+	}
+}
 
-		for i := 0; i < len(pairs); i++ {
-			buf.WriteString(" ,?")
+// loadData inserts the records by LOAD DATA LOCAL INFILE from a registered reader. Like
+// INSERT IGNORE, the records of the existing keys are skipped.
+func (db *mysqlDB) loadData(ctx context.Context, table string, keys []string, fields []string, values []map[string][]byte) error {
+	buf := new(bytes.Buffer)
+	for i, key := range keys {
+		escapeLoadData(buf, []byte(db.rowKey(key)))
+		for _, field := range fields {
+			buf.WriteByte('\t')
+			if v, ok := values[i][field]; ok {
+				escapeLoadData(buf, v)
+			} else {
+				buf.WriteString(`\N`)
+			}
 		}
+		buf.WriteByte('\n')
+	}
 
-		buf.WriteByte(')')
-		err = db.execQuery(ctx, buf.String(), args...)
+	name := fmt.Sprintf("ycsb_%d", atomic.AddInt64(&loadDataID, 1))
+	mysql.RegisterReaderHandler(name, func() io.Reader {
+		return buf
+	})
+	defer mysql.DeregisterReaderHandler(name)
+
+	query := fmt.Sprintf("LOAD DATA LOCAL INFILE 'Reader::%s' INTO TABLE %s CHARACTER SET binary (YCSB_KEY, %s)", name, table, strings.Join(fields, ", "))
+	if db.verbose {
+		fmt.Println(query)
 	}
+
+	state := ctx.Value(stateKey).(*mysqlState)
+	_, err := state.conn.ExecContext(ctx, query)
 	return err
 }
 
@@ -480,11 +763,93 @@ func (db *mysqlDB) Delete(ctx context.Context, table string, key string) error {
 	return db.execQuery(ctx, query, key)
 }
 
+// BatchDelete deletes the records by the DELETEs of the keys, each with at most maxParams
+// keys.
+func (db *mysqlDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	for start := 0; start < len(keys); start += maxParams {
+		end := start + maxParams
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		query := fmt.Sprintf(`DELETE FROM %s WHERE YCSB_KEY IN (%s)`, table, placeholders(end-start))
+		if err := db.execQuery(ctx, query, keyArgs(keys[start:end])...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *mysqlDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE YCSB_KEY >= ? AND YCSB_KEY < ?`, table)
+
+	return db.execQuery(ctx, query, startKey, endKey)
+}
+
 func (db *mysqlDB) Analyze(ctx context.Context, table string) error {
 	_, err := db.db.Exec(fmt.Sprintf(`ANALYZE TABLE %s`, table))
 	return err
 }
 
+func (db *mysqlDB) CreateIndex(ctx context.Context, table string, field string) error {
+	query := fmt.Sprintf(`CREATE INDEX idx_%s_%s ON %s (%s)`, table, field, table, field)
+	if db.verbose {
+		fmt.Println(query)
+	}
+
+	_, err := db.db.ExecContext(ctx, query)
+	if e, ok := err.(*mysql.MySQLError); ok && e.Number == 1061 {
+		// Duplicate key name, the index has been created.
+		return nil
+	}
+	return err
+}
+
+func (db *mysqlDB) Query(ctx context.Context, table string, field string, value []byte, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE %s = ?`, table, field)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE %s = ?`, strings.Join(fields, ","), table, field)
+	}
+
+	rows, err := db.queryRows(ctx, query, 1, value)
+	db.clearCacheIfFailed(ctx, query, err)
+
+	return rows, err
+}
+
+func (db *mysqlDB) RangeQuery(ctx context.Context, table string, field string, start []byte, count int, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE %s >= ? ORDER BY %s LIMIT ?`, table, field, field)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE %s >= ? ORDER BY %s LIMIT ?`, strings.Join(fields, ","), table, field, field)
+	}
+
+	rows, err := db.queryRows(ctx, query, count, start, count)
+	db.clearCacheIfFailed(ctx, query, err)
+
+	return rows, err
+}
+
+func (db *mysqlDB) Count(ctx context.Context, table string, field string, start []byte, end []byte) (int64, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s BETWEEN ? AND ?`, table, field)
+	if db.verbose {
+		fmt.Printf("%s %v\n", query, []interface{}{start, end})
+	}
+
+	stmt, err := db.getAndCacheStmt(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	err = stmt.QueryRowContext(ctx, start, end).Scan(&n)
+	db.clearCacheIfFailed(ctx, query, err)
+	return n, err
+}
+
 func init() {
 	ycsb.RegisterDBCreator("mysql", mysqlCreator{})
 	ycsb.RegisterDBCreator("tidb", mysqlCreator{})
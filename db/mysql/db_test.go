@@ -0,0 +1,42 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEscapeLoadData(t *testing.T) {
+	buf := new(bytes.Buffer)
+	escapeLoadData(buf, []byte("a\\b\tc\nd\x00e'\"f"))
+	if want := `a\\b\tc\nd\0e'"f`; buf.String() != want {
+		t.Fatalf("want %s, but got %s", want, buf.String())
+	}
+}
+
+func TestInsertFields(t *testing.T) {
+	values := []map[string][]byte{{"field1": []byte("a")}, {"field0": []byte("b"), "field2": []byte("c")}}
+	fields := insertFields(values...)
+	if want := []string{"field0", "field1", "field2"}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("want %q, but got %q", want, fields)
+	}
+
+	// The missing fields are NULL.
+	args := insertArgs(nil, "k1", fields, values[0])
+	if want := []interface{}{"k1", nil, []byte("a"), nil}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("want %q, but got %q", want, args)
+	}
+}
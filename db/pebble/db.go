@@ -0,0 +1,361 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// properties
+const (
+	pebbleDir = "pebble.dir"
+	// Options
+	pebbleBytesPerSync                = "pebble.bytes_per_sync"
+	pebbleCacheSize                   = "pebble.cache_size"
+	pebbleDisableWAL                  = "pebble.disable_wal"
+	pebbleL0CompactionThreshold       = "pebble.l0_compaction_threshold"
+	pebbleL0StopWritesThreshold       = "pebble.l0_stop_writes_threshold"
+	pebbleLBaseMaxBytes               = "pebble.lbase_max_bytes"
+	pebbleMaxConcurrentCompactions    = "pebble.max_concurrent_compactions"
+	pebbleMaxOpenFiles                = "pebble.max_open_files"
+	pebbleMemTableSize                = "pebble.mem_table_size"
+	pebbleMemTableStopWritesThreshold = "pebble.mem_table_stop_writes_threshold"
+	pebbleNumLevels                   = "pebble.num_levels"
+	pebbleSyncWrites                  = "pebble.sync_writes"
+	pebbleWALBytesPerSync             = "pebble.wal_bytes_per_sync"
+	pebbleWALDir                      = "pebble.wal_dir"
+	// LevelOptions
+	pebbleBlockRestartInterval = "pebble.block_restart_interval"
+	pebbleBlockSize            = "pebble.block_size"
+	pebbleBlockSizeThreshold   = "pebble.block_size_threshold"
+	pebbleBloomBitsPerKey      = "pebble.bloom_bits_per_key"
+	pebbleCompression          = "pebble.compression"
+	pebbleIndexBlockSize       = "pebble.index_block_size"
+	pebbleTargetFileSize       = "pebble.target_file_size"
+)
+
+type pebbleCreator struct {
+}
+
+type pebbleDB struct {
+	p *properties.Properties
+
+	db *pebble.DB
+
+	r       *util.RowCodec
+	bufPool *util.BufPool
+
+	writeOpts *pebble.WriteOptions
+}
+
+func (c pebbleCreator) Create(p *properties.Properties) (ycsb.DB, error) {
+	dir := p.GetString(pebbleDir, "/tmp/pebble")
+
+	if p.GetBool(prop.DropData, prop.DropDataDefault) {
+		os.RemoveAll(dir)
+		if walDir := p.GetString(pebbleWALDir, ""); len(walDir) > 0 {
+			os.RemoveAll(walDir)
+		}
+	}
+
+	opts, err := getOptions(p)
+	if err != nil {
+		return nil, err
+	}
+	// The db holds its own reference of the cache.
+	defer opts.Cache.Unref()
+
+	db, err := pebble.Open(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	writeOpts := pebble.NoSync
+	if p.GetBool(pebbleSyncWrites, false) {
+		writeOpts = pebble.Sync
+	}
+
+	return &pebbleDB{
+		p:         p,
+		db:        db,
+		r:         util.NewRowCodec(p),
+		bufPool:   util.NewBufPool(),
+		writeOpts: writeOpts,
+	}, nil
+}
+
+func getLevelOptions(p *properties.Properties) ([]pebble.LevelOptions, error) {
+	var compression pebble.Compression
+	switch c := p.GetString(pebbleCompression, "snappy"); c {
+	case "snappy":
+		compression = pebble.SnappyCompression
+	case "none":
+		compression = pebble.NoCompression
+	default:
+		return nil, fmt.Errorf("unknown %s %s", pebbleCompression, c)
+	}
+
+	var filterPolicy pebble.FilterPolicy
+	if bitsPerKey := p.GetInt(pebbleBloomBitsPerKey, 10); bitsPerKey > 0 {
+		filterPolicy = bloom.FilterPolicy(bitsPerKey)
+	}
+
+	levels := make([]pebble.LevelOptions, p.GetInt(pebbleNumLevels, 7))
+	targetFileSize := p.GetInt64(pebbleTargetFileSize, 2<<20)
+	for i := range levels {
+		l := &levels[i]
+		l.BlockRestartInterval = p.GetInt(pebbleBlockRestartInterval, 16)
+		l.BlockSize = p.GetInt(pebbleBlockSize, 4<<10)
+		l.BlockSizeThreshold = p.GetInt(pebbleBlockSizeThreshold, 90)
+		l.IndexBlockSize = p.GetInt(pebbleIndexBlockSize, l.BlockSize)
+		l.Compression = compression
+		l.FilterPolicy = filterPolicy
+		// Every level has files twice as big as the level above.
+		l.TargetFileSize = targetFileSize << uint(i)
+	}
+
+	return levels, nil
+}
+
+func getOptions(p *properties.Properties) (*pebble.Options, error) {
+	levels, err := getLevelOptions(p)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &pebble.Options{
+		BytesPerSync:                p.GetInt(pebbleBytesPerSync, 512<<10),
+		Cache:                       pebble.NewCache(p.GetInt64(pebbleCacheSize, 8<<20)),
+		DisableWAL:                  p.GetBool(pebbleDisableWAL, false),
+		L0CompactionThreshold:       p.GetInt(pebbleL0CompactionThreshold, 4),
+		L0StopWritesThreshold:       p.GetInt(pebbleL0StopWritesThreshold, 12),
+		LBaseMaxBytes:               p.GetInt64(pebbleLBaseMaxBytes, 64<<20),
+		Levels:                      levels,
+		MaxConcurrentCompactions:    p.GetInt(pebbleMaxConcurrentCompactions, 1),
+		MaxOpenFiles:                p.GetInt(pebbleMaxOpenFiles, 1000),
+		MemTableSize:                p.GetInt(pebbleMemTableSize, 4<<20),
+		MemTableStopWritesThreshold: p.GetInt(pebbleMemTableStopWritesThreshold, 2),
+		WALBytesPerSync:             p.GetInt(pebbleWALBytesPerSync, 0),
+		WALDir:                      p.GetString(pebbleWALDir, ""),
+	}
+
+	return opts.EnsureDefaults(), nil
+}
+
+func (db *pebbleDB) Close() error {
+	return db.db.Close()
+}
+
+func (db *pebbleDB) InitThread(ctx context.Context, _ int, _ int) context.Context {
+	return ctx
+}
+
+func (db *pebbleDB) CleanupThread(_ context.Context) {
+}
+
+func (db *pebbleDB) getRowKey(table string, key string) []byte {
+	return util.Slice(fmt.Sprintf("%s:%s", table, key))
+}
+
+// get returns a copy of the row, because the value is only valid until the closer is closed.
+func (db *pebbleDB) get(rowKey []byte) ([]byte, error) {
+	value, closer, err := db.db.Get(rowKey)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return append([]byte(nil), value...), nil
+}
+
+func (db *pebbleDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	row, err := db.get(db.getRowKey(table, key))
+	if err != nil {
+		return nil, err
+	}
+
+	return db.r.Decode(row, fields)
+}
+
+func (db *pebbleDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	rows := make([]map[string][]byte, 0, len(keys))
+	for _, key := range keys {
+		m, err := db.Read(ctx, table, key, fields)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, m)
+	}
+
+	return rows, nil
+}
+
+func (db *pebbleDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
+	// The row keys of the table end before "<table>;", because ';' follows ':'.
+	return db.scan(db.getRowKey(table, startKey), []byte(table+";"), count, fields)
+}
+
+func (db *pebbleDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(db.getRowKey(table, startKey), db.getRowKey(table, endKey), limit, fields)
+}
+
+// scan reads at most count rows from rowStartKey, and stops before rowEndKey if it is not nil.
+func (db *pebbleDB) scan(rowStartKey []byte, rowEndKey []byte, count int, fields []string) ([]map[string][]byte, error) {
+	res := make([]map[string][]byte, 0, count)
+	it := db.db.NewIter(&pebble.IterOptions{
+		LowerBound: rowStartKey,
+		UpperBound: rowEndKey,
+	})
+	defer it.Close()
+
+	for it.SeekGE(rowStartKey); it.Valid() && len(res) < count; it.Next() {
+		m, err := db.r.Decode(append([]byte(nil), it.Value()...), fields)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// update merges the values into the stored row, and returns the encoded row in buf.
+func (db *pebbleDB) update(buf []byte, rowKey []byte, values map[string][]byte) ([]byte, error) {
+	row, err := db.get(rowKey)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := db.r.Decode(row, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for field, value := range values {
+		m[field] = value
+	}
+
+	return db.r.Encode(buf, m)
+}
+
+func (db *pebbleDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	rowKey := db.getRowKey(table, key)
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	rowData, err := db.update(buf.Bytes(), rowKey, values)
+	if err != nil {
+		return err
+	}
+
+	return db.db.Set(rowKey, rowData, db.writeOpts)
+}
+
+func (db *pebbleDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	b := db.db.NewBatch()
+	defer b.Close()
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	// The batch copies the row, so the buffer is reused for every row.
+	rowData := buf.Bytes()
+	for i, key := range keys {
+		rowKey := db.getRowKey(table, key)
+		var err error
+		if rowData, err = db.update(rowData, rowKey, values[i]); err != nil {
+			return err
+		}
+		if err = b.Set(rowKey, rowData, nil); err != nil {
+			return err
+		}
+	}
+
+	return b.Commit(db.writeOpts)
+}
+
+func (db *pebbleDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	rowKey := db.getRowKey(table, key)
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	rowData, err := db.r.Encode(buf.Bytes(), values)
+	if err != nil {
+		return err
+	}
+
+	return db.db.Set(rowKey, rowData, db.writeOpts)
+}
+
+func (db *pebbleDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	b := db.db.NewBatch()
+	defer b.Close()
+
+	buf := db.bufPool.Get()
+	defer db.bufPool.Put(buf)
+
+	// The batch copies the row, so the buffer is reused for every row.
+	rowData := buf.Bytes()
+	for i, key := range keys {
+		var err error
+		if rowData, err = db.r.Encode(rowData, values[i]); err != nil {
+			return err
+		}
+		if err = b.Set(db.getRowKey(table, key), rowData, nil); err != nil {
+			return err
+		}
+	}
+
+	return b.Commit(db.writeOpts)
+}
+
+func (db *pebbleDB) Delete(ctx context.Context, table string, key string) error {
+	return db.db.Delete(db.getRowKey(table, key), db.writeOpts)
+}
+
+func (db *pebbleDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	b := db.db.NewBatch()
+	defer b.Close()
+
+	for _, key := range keys {
+		if err := b.Delete(db.getRowKey(table, key), nil); err != nil {
+			return err
+		}
+	}
+
+	return b.Commit(db.writeOpts)
+}
+
+func (db *pebbleDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	return db.db.DeleteRange(db.getRowKey(table, startKey), db.getRowKey(table, endKey), db.writeOpts)
+}
+
+func init() {
+	ycsb.RegisterDBCreator("pebble", pebbleCreator{})
+}
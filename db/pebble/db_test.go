@@ -0,0 +1,62 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pebble
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/dbtest"
+)
+
+func newTestDB(t *testing.T) (*pebbleDB, func()) {
+	dir, err := ioutil.TempDir("", "pebble")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := properties.NewProperties()
+	p.Set(pebbleDir, dir)
+	p.Set("fieldcount", "2")
+	db, err := pebbleCreator{}.Create(p)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return db.(*pebbleDB), func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCRUD(t *testing.T) {
+	db, clean := newTestDB(t)
+	defer clean()
+	dbtest.TestCRUD(t, db)
+}
+
+func TestScan(t *testing.T) {
+	db, clean := newTestDB(t)
+	defer clean()
+	dbtest.TestScan(t, db)
+}
+
+func TestBatch(t *testing.T) {
+	db, clean := newTestDB(t)
+	defer clean()
+	dbtest.TestBatch(t, db)
+}
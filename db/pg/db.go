@@ -18,13 +18,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pingcap/go-ycsb/pkg/prop"
 	"github.com/pingcap/go-ycsb/pkg/util"
 
 	// pg package
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/magiconair/properties"
 	"github.com/pingcap/go-ycsb/pkg/ycsb"
 )
@@ -37,7 +38,8 @@ const (
 	pgPassword = "pg.password"
 	pgDBName   = "pg.db"
 	pdSSLMode  = "pg.sslmode"
-	// TODO: support batch and auto commit
+	pgBulkLoad = "pg.bulk_load"
+	// TODO: support auto commit
 )
 
 type pgCreator struct {
@@ -47,6 +49,10 @@ type pgDB struct {
 	p       *properties.Properties
 	db      *sql.DB
 	verbose bool
+	// bulkLoad inserts the batches by COPY FROM STDIN instead of a multi-row INSERT.
+	bulkLoad bool
+	// bytesFields are the BYTEA fields, and the other fields are copied as text.
+	bytesFields map[string]bool
 
 	bufPool *util.BufPool
 
@@ -68,6 +74,11 @@ func (c pgCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	d := new(pgDB)
 	d.p = p
 
+	// The binary keys may be invalid strings or contain NUL for the VARCHAR key.
+	if strings.ToLower(p.GetString(prop.KeyFormat, prop.KeyFormatDefault)) == "binary" {
+		return nil, fmt.Errorf("pg doesn't support keyformat=binary")
+	}
+
 	host := p.GetString(pgHost, "127.0.0.1")
 	port := p.GetInt(pgPort, 5432)
 	user := p.GetString(pgUser, "root")
@@ -88,6 +99,7 @@ func (c pgCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	db.SetMaxOpenConns(threadCount * 2)
 
 	d.verbose = p.GetBool(prop.Verbose, prop.VerboseDefault)
+	d.bulkLoad = p.GetBool(pgBulkLoad, false)
 	d.db = db
 	d.dbName = dbName
 
@@ -109,15 +121,18 @@ func (db *pgDB) createTable() error {
 		}
 	}
 
-	fieldCount := db.p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
-	fieldLength := db.p.GetInt64(prop.FieldLength, prop.FieldLengthDefault)
+	schema := util.LoadSchema(db.p)
+	db.bytesFields = make(map[string]bool, len(schema.Fields))
+	for _, f := range schema.Fields {
+		db.bytesFields[f.Name] = f.Type == util.FieldTypeBytes
+	}
 
 	buf := new(bytes.Buffer)
 	s := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (YCSB_KEY VARCHAR(64) PRIMARY KEY", tableName)
 	buf.WriteString(s)
 
-	for i := int64(0); i < fieldCount; i++ {
-		buf.WriteString(fmt.Sprintf(", FIELD%d VARCHAR(%d)", i, fieldLength))
+	for _, f := range schema.Fields {
+		buf.WriteString(fmt.Sprintf(", %s %s", f.Name, columnType(f)))
 	}
 
 	buf.WriteString(");")
@@ -126,10 +141,49 @@ func (db *pgDB) createTable() error {
 		fmt.Println(buf.String())
 	}
 
-	_, err := db.db.Exec(buf.String())
+	if _, err := db.db.Exec(buf.String()); err != nil {
+		return err
+	}
+	return db.createMetadataTable(schema)
+}
+
+// createMetadataTable creates the table of the load metadata, whose field keeps the
+// metadata in JSON.
+func (db *pgDB) createMetadataTable(schema *util.Schema) error {
+	tableName := util.MetadataTable(db.p)
+	f := schema.MetadataField()
+	if len(tableName) == 0 || f == nil {
+		return nil
+	}
+
+	if db.p.GetBool(prop.DropData, prop.DropDataDefault) {
+		if _, err := db.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName)); err != nil {
+			return err
+		}
+	}
+
+	colType := "TEXT"
+	if f.Type == util.FieldTypeBytes {
+		colType = "BYTEA"
+	}
+	_, err := db.db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (YCSB_KEY VARCHAR(64) PRIMARY KEY, %s %s);", tableName, f.Name, colType))
 	return err
 }
 
+// columnType returns the column type of the field in the schema.
+func columnType(f util.SchemaField) string {
+	switch f.Type {
+	case util.FieldTypeBytes:
+		return "BYTEA"
+	case util.FieldTypeInt:
+		return "BIGINT"
+	case util.FieldTypeTimestamp:
+		return "TIMESTAMP"
+	default:
+		return fmt.Sprintf("VARCHAR(%d)", f.Length)
+	}
+}
+
 func (db *pgDB) Close() error {
 	if db.db == nil {
 		return nil
@@ -258,6 +312,63 @@ func (db *pgDB) Read(ctx context.Context, table string, key string, fields []str
 	return rows[0], nil
 }
 
+// placeholders returns n comma-separated placeholders from $start.
+func placeholders(start int, n int) string {
+	ps := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		ps = append(ps, fmt.Sprintf("$%d", start+i))
+	}
+	return strings.Join(ps, ", ")
+}
+
+// BatchRead reads the records by the queries of the keys, each with at most maxParams keys.
+// Like Read, the records of the missing keys are nil.
+func (db *pgDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	byKey := make(map[string]map[string][]byte, len(keys))
+	for start := 0; start < len(keys); start += maxParams {
+		end := start + maxParams
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		var query string
+		if len(fields) == 0 {
+			query = fmt.Sprintf(`SELECT * FROM %s WHERE YCSB_KEY IN (%s)`, table, placeholders(1, end-start))
+		} else {
+			query = fmt.Sprintf(`SELECT YCSB_KEY, %s FROM %s WHERE YCSB_KEY IN (%s)`, strings.Join(fields, ","), table, placeholders(1, end-start))
+		}
+
+		rows, err := db.queryRows(ctx, query, end-start, keyArgs(keys[start:end])...)
+		db.clearCacheIfFailed(ctx, query, err)
+		if err != nil {
+			return nil, err
+		}
+
+		// The unquoted column names are lower case.
+		for _, row := range rows {
+			byKey[string(row["ycsb_key"])] = row
+			if len(fields) > 0 {
+				delete(row, "ycsb_key")
+			}
+		}
+	}
+
+	res := make([]map[string][]byte, len(keys))
+	for i, key := range keys {
+		res[i] = byKey[key]
+	}
+	return res, nil
+}
+
+// keyArgs returns the keys as the arguments of a statement.
+func keyArgs(keys []string) []interface{} {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return args
+}
+
 func (db *pgDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
 	var query string
 	if len(fields) == 0 {
@@ -272,6 +383,20 @@ func (db *pgDB) Scan(ctx context.Context, table string, startKey string, count i
 	return rows, err
 }
 
+func (db *pgDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE YCSB_KEY >= $1 AND YCSB_KEY < $2 LIMIT $3`, table)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE YCSB_KEY >= $1 AND YCSB_KEY < $2 LIMIT $3`, strings.Join(fields, ","), table)
+	}
+
+	rows, err := db.queryRows(ctx, query, limit, startKey, endKey, limit)
+	db.clearCacheIfFailed(ctx, query, err)
+
+	return rows, err
+}
+
 func (db *pgDB) execQuery(ctx context.Context, query string, args ...interface{}) error {
 	if db.verbose {
 		fmt.Printf("%s %v\n", query, args)
@@ -287,7 +412,7 @@ func (db *pgDB) execQuery(ctx context.Context, query string, args ...interface{}
 	return err
 }
 
-func (db *pgDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+func (db *pgDB) updateQuery(table string, key string, values map[string][]byte) (string, []interface{}) {
 	buf := db.bufPool.Get()
 	defer db.bufPool.Put(buf)
 
@@ -313,34 +438,240 @@ func (db *pgDB) Update(ctx context.Context, table string, key string, values map
 
 	args = append(args, key)
 
-	return db.execQuery(ctx, buf.String(), args...)
+	return buf.String(), args
 }
 
-func (db *pgDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
-	args := make([]interface{}, 0, 1+len(values))
-	args = append(args, key)
+func (db *pgDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	query, args := db.updateQuery(table, key, values)
+	return db.execQuery(ctx, query, args...)
+}
 
+// BatchUpdate updates the records one by one in a transaction.
+func (db *pgDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	state := ctx.Value(stateKey).(*pgState)
+	tx, err := state.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		query, args := db.updateQuery(table, key, values[i])
+		if db.verbose {
+			fmt.Printf("%s %v\n", query, args)
+		}
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertQuery builds the INSERT of rowCount rows of the fields.
+func (db *pgDB) insertQuery(table string, fields []string, rowCount int) string {
 	buf := db.bufPool.Get()
 	defer db.bufPool.Put(buf)
 
 	buf.WriteString("INSERT INTO ")
 	buf.WriteString(table)
 	buf.WriteString(" (YCSB_KEY")
-	pairs := util.NewFieldPairs(values)
-	for _, p := range pairs {
-		args = append(args, p.Value)
+	for _, field := range fields {
 		buf.WriteString(" ,")
-		buf.WriteString(p.Field)
+		buf.WriteString(field)
+	}
+	buf.WriteString(") VALUES ")
+
+	for i := 0; i < rowCount; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('(')
+		buf.WriteString(placeholders(i*(1+len(fields))+1, 1+len(fields)))
+		buf.WriteByte(')')
+	}
+
+	buf.WriteString(" ON CONFLICT DO NOTHING")
+
+	return buf.String()
+}
+
+// maxParams is the max number of the parameters of a statement in PostgreSQL.
+const maxParams = 65535
+
+// insertFields returns the sorted fields of all the values, and the records of a batch
+// can have different fields.
+func insertFields(values ...map[string][]byte) []string {
+	seen := make(map[string]struct{})
+	var fields []string
+	for _, m := range values {
+		for field := range m {
+			if _, ok := seen[field]; !ok {
+				seen[field] = struct{}{}
+				fields = append(fields, field)
+			}
+		}
 	}
-	buf.WriteString(") VALUES ($1")
+	sort.Strings(fields)
+	return fields
+}
 
-	for i := 0; i < len(pairs); i++ {
-		buf.WriteString(fmt.Sprintf(" ,$%d", i+2))
+// insertArgs appends the key and the values of the fields, and the missing fields are NULL.
+func insertArgs(args []interface{}, key string, fields []string, values map[string][]byte) []interface{} {
+	args = append(args, key)
+	for _, field := range fields {
+		if v, ok := values[field]; ok {
+			args = append(args, v)
+		} else {
+			args = append(args, nil)
+		}
 	}
+	return args
+}
 
-	buf.WriteString(") ON CONFLICT DO NOTHING")
+// batchInsert inserts the records by the multi-row INSERTs, each with at most
+// maxParams parameters.
+func (db *pgDB) batchInsert(ctx context.Context, table string, keys []string, fields []string, values []map[string][]byte) error {
+	rowCount := maxParams / (1 + len(fields))
+	for start := 0; start < len(keys); start += rowCount {
+		end := start + rowCount
+		if end > len(keys) {
+			end = len(keys)
+		}
 
-	return db.execQuery(ctx, buf.String(), args...)
+		args := make([]interface{}, 0, (end-start)*(1+len(fields)))
+		for i := start; i < end; i++ {
+			args = insertArgs(args, keys[i], fields, values[i])
+		}
+		if err := db.execQuery(ctx, db.insertQuery(table, fields, end-start), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *pgDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	fields := insertFields(values)
+	args := insertArgs(make([]interface{}, 0, 1+len(fields)), key, fields, values)
+
+	return db.execQuery(ctx, db.insertQuery(table, fields, 1), args...)
+}
+
+// BatchInsert inserts the records by the multi-row INSERTs, or with pg.bulk_load, by COPY
+// FROM STDIN. The fields of the records are the fields of all the records.
+func (db *pgDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	fields := insertFields(values...)
+	if db.bulkLoad {
+		return db.copyIn(ctx, table, keys, fields, values)
+	}
+	return db.batchInsert(ctx, table, keys, fields, values)
+}
+
+// copyTable is the temporary table of a COPY, which is dropped when the transaction ends.
+const copyTable = "ycsb_copy"
+
+// copyColumns returns the names of the key and the fields, and the unquoted names of the
+// table are lower case.
+func copyColumns(fields []string) []string {
+	columns := make([]string, 0, 1+len(fields))
+	columns = append(columns, "ycsb_key")
+	for _, field := range fields {
+		columns = append(columns, strings.ToLower(field))
+	}
+	return columns
+}
+
+// copyQueries builds the queries of a COPY: creating the temporary table like the table,
+// the COPY FROM STDIN into it, which quotes the names, and the INSERT of the copied rows,
+// which skips the existing keys like the multi-row INSERTs.
+func copyQueries(table string, fields []string) (create string, load string, insert string) {
+	columns := strings.Join(copyColumns(fields), ", ")
+	create = fmt.Sprintf(`CREATE TEMPORARY TABLE %s (LIKE %s) ON COMMIT DROP`, copyTable, table)
+	load = pq.CopyIn(copyTable, copyColumns(fields)...)
+	insert = fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT DO NOTHING`, table, columns, columns, copyTable)
+	return
+}
+
+// copyIn inserts the records by COPY FROM STDIN into a temporary table and an INSERT of the
+// copied rows in a transaction, so the existing keys are skipped like the INSERTs.
+func (db *pgDB) copyIn(ctx context.Context, table string, keys []string, fields []string, values []map[string][]byte) error {
+	create, query, insert := copyQueries(table, fields)
+	if db.verbose {
+		fmt.Println(create)
+		fmt.Println(query)
+		fmt.Println(insert)
+	}
+
+	state := ctx.Value(stateKey).(*pgState)
+	tx, err := state.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, create); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	args := make([]interface{}, 0, 1+len(fields))
+	for i, key := range keys {
+		args = append(args[:0], key)
+		for _, field := range fields {
+			// The []byte values are copied as BYTEA.
+			if v, ok := values[i][field]; !ok {
+				args = append(args, nil)
+			} else if db.bytesFields[field] {
+				args = append(args, v)
+			} else {
+				args = append(args, string(v))
+			}
+		}
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			stmt.Close()
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// The empty Exec flushes the copied rows.
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		tx.Rollback()
+		return err
+	}
+	if err = stmt.Close(); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, insert); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// BatchDelete deletes the records by the DELETEs of the keys, each with at most maxParams
+// keys.
+func (db *pgDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	for start := 0; start < len(keys); start += maxParams {
+		end := start + maxParams
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		query := fmt.Sprintf(`DELETE FROM %s WHERE YCSB_KEY IN (%s)`, table, placeholders(1, end-start))
+		if err := db.execQuery(ctx, query, keyArgs(keys[start:end])...); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (db *pgDB) Delete(ctx context.Context, table string, key string) error {
@@ -349,6 +680,67 @@ func (db *pgDB) Delete(ctx context.Context, table string, key string) error {
 	return db.execQuery(ctx, query, key)
 }
 
+func (db *pgDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE YCSB_KEY >= $1 AND YCSB_KEY < $2`, table)
+
+	return db.execQuery(ctx, query, startKey, endKey)
+}
+
+func (db *pgDB) CreateIndex(ctx context.Context, table string, field string) error {
+	query := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_%s ON %s (%s)`, table, field, table, field)
+	if db.verbose {
+		fmt.Println(query)
+	}
+
+	_, err := db.db.ExecContext(ctx, query)
+	return err
+}
+
+func (db *pgDB) Query(ctx context.Context, table string, field string, value []byte, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE %s = $1`, table, field)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE %s = $1`, strings.Join(fields, ","), table, field)
+	}
+
+	rows, err := db.queryRows(ctx, query, 1, value)
+	db.clearCacheIfFailed(ctx, query, err)
+
+	return rows, err
+}
+
+func (db *pgDB) RangeQuery(ctx context.Context, table string, field string, start []byte, count int, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE %s >= $1 ORDER BY %s LIMIT $2`, table, field, field)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE %s >= $1 ORDER BY %s LIMIT $2`, strings.Join(fields, ","), table, field, field)
+	}
+
+	rows, err := db.queryRows(ctx, query, count, start, count)
+	db.clearCacheIfFailed(ctx, query, err)
+
+	return rows, err
+}
+
+func (db *pgDB) Count(ctx context.Context, table string, field string, start []byte, end []byte) (int64, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s BETWEEN $1 AND $2`, table, field)
+	if db.verbose {
+		fmt.Printf("%s %v\n", query, []interface{}{start, end})
+	}
+
+	stmt, err := db.getAndCacheStmt(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	err = stmt.QueryRowContext(ctx, start, end).Scan(&n)
+	db.clearCacheIfFailed(ctx, query, err)
+	return n, err
+}
+
 func init() {
 	ycsb.RegisterDBCreator("pg", pgCreator{})
 	ycsb.RegisterDBCreator("postgresql", pgCreator{})
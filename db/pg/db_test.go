@@ -0,0 +1,32 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"testing"
+)
+
+func TestCopyQueries(t *testing.T) {
+	fields := insertFields(map[string][]byte{"FIELD1": []byte("a")}, map[string][]byte{"field0": []byte("b")})
+	create, load, insert := copyQueries("UserTable", fields)
+	if want := `CREATE TEMPORARY TABLE ycsb_copy (LIKE UserTable) ON COMMIT DROP`; create != want {
+		t.Fatalf("want %s, but got %s", want, create)
+	}
+	if want := `COPY "ycsb_copy" ("ycsb_key", "field1", "field0") FROM STDIN`; load != want {
+		t.Fatalf("want %s, but got %s", want, load)
+	}
+	if want := `INSERT INTO UserTable (ycsb_key, field1, field0) SELECT ycsb_key, field1, field0 FROM ycsb_copy ON CONFLICT DO NOTHING`; insert != want {
+		t.Fatalf("want %s, but got %s", want, insert)
+	}
+}
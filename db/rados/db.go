@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"github.com/journeymidnight/radoshttpd/rados"
 	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
 	"github.com/pingcap/go-ycsb/pkg/ycsb"
 	"math/rand"
 	"os"
@@ -37,9 +39,10 @@ const stateKey = contextKey("radosClient")
 type radosCreator struct{}
 
 type radosOptions struct {
-	Path string
-	Pool string
-	Size uint64
+	Path           string
+	Pool           string
+	Size           uint64
+	ValueGenerator util.ValueGenerator
 }
 
 type radosClient struct {
@@ -49,6 +52,7 @@ type radosClient struct {
 	fsid       string
 	instanceId uint64
 	size       uint64
+	valueGen   util.ValueGenerator
 }
 
 type radosState struct {
@@ -59,7 +63,10 @@ type radosState struct {
 }
 
 func (r radosCreator) Create(p *properties.Properties) (ycsb.DB, error) {
-	opts := getOptions(p)
+	opts, err := getOptions(p)
+	if err != nil {
+		return nil, err
+	}
 	fi, err := os.Stat(opts.Path)
 	if err != nil {
 		return nil, err
@@ -100,22 +107,31 @@ func (r radosCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 		instanceId: id,
 		pool:       opts.Pool,
 		size:       opts.Size,
+		valueGen:   opts.ValueGenerator,
 	}
 	return c, nil
 }
 
-func getOptions(p *properties.Properties) radosOptions {
+func getOptions(p *properties.Properties) (radosOptions, error) {
 	path := p.GetString(cephPath, "/etc/ceph.conf")
 	pool := p.GetString(poolName, "rabbit")
 	size, err := humanize.ParseBytes(p.GetString(objectSize, "4KiB"))
 	if err != nil {
-		panic(err)
+		return radosOptions{}, err
 	}
-	return radosOptions{
-		Path: path,
-		Pool: pool,
-		Size: size,
+	var valueGenerator util.ValueGenerator
+	if _, ok := p.Get(prop.ValueGenerator); ok {
+		valueGenerator, err = util.NewValueGenerator(p)
+		if err != nil {
+			return radosOptions{}, err
+		}
 	}
+	return radosOptions{
+		Path:           path,
+		Pool:           pool,
+		Size:           size,
+		ValueGenerator: valueGenerator,
+	}, nil
 }
 
 // Close closes the database layer.
@@ -128,8 +144,12 @@ func (r *radosClient) Close() error {
 // The Returned context will be passed to the following usage.
 func (r *radosClient) InitThread(ctx context.Context, threadID int, threadCount int) context.Context {
 	mockData := make([]byte, r.size)
-	for i := 0; i < len(mockData); i++ {
-		mockData[i] = uint8(i % 255)
+	if r.valueGen != nil {
+		r.valueGen.Fill(rand.New(rand.NewSource(time.Now().UnixNano())), mockData)
+	} else {
+		for i := 0; i < len(mockData); i++ {
+			mockData[i] = uint8(i % 255)
+		}
 	}
 
 	state := &radosState{
@@ -206,6 +226,94 @@ func (r *radosClient) Delete(ctx context.Context, table string, key string) erro
 	return nil
 }
 
+// PutObject implements the ObjectDB PutObject interface. The bucket is ignored, the
+// configured pool is used.
+func (r *radosClient) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	pool, err := r.conn.OpenPool(r.pool)
+	if err != nil {
+		return err
+	}
+	defer pool.Destroy()
+	return pool.WriteSmallObject(key, data)
+}
+
+// GetObject implements the ObjectDB GetObject interface. The bucket is ignored, the
+// configured pool is used.
+func (r *radosClient) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error) {
+	pool, err := r.conn.OpenPool(r.pool)
+	if err != nil {
+		return nil, err
+	}
+	defer pool.Destroy()
+
+	if length > 0 {
+		data := make([]byte, length)
+		n, err := pool.Read(key, data, uint64(offset))
+		if err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	}
+
+	// Read to the end of the object with BUFFER_SIZE chunks.
+	var data []byte
+	buf := make([]byte, BUFFER_SIZE)
+	for {
+		n, err := pool.Read(key, buf, uint64(offset)+uint64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, buf[:n]...)
+		if n < len(buf) {
+			return data, nil
+		}
+	}
+}
+
+// HeadObject implements the ObjectDB HeadObject interface. The rados binding has no object
+// stat, so the range GETs need the constant object size.
+func (r *radosClient) HeadObject(ctx context.Context, bucket string, key string) (int64, error) {
+	return 0, fmt.Errorf("rados: head is %w", ycsb.ErrNotSupported)
+}
+
+// ListObjects implements the ObjectDB ListObjects interface.
+func (r *radosClient) ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error) {
+	return nil, errors.New("list is not supported")
+}
+
+// DeleteObject implements the ObjectDB DeleteObject interface. The bucket is ignored, the
+// configured pool is used.
+func (r *radosClient) DeleteObject(ctx context.Context, bucket string, key string) error {
+	pool, err := r.conn.OpenPool(r.pool)
+	if err != nil {
+		return err
+	}
+	defer pool.Destroy()
+	return pool.Delete(key)
+}
+
+// MultipartUpload implements the ObjectDB MultipartUpload interface. RADOS has no multipart
+// upload, so every part is written at its offset of the object. The bucket is ignored, the
+// configured pool is used.
+func (r *radosClient) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error {
+	pool, err := r.conn.OpenPool(r.pool)
+	if err != nil {
+		return err
+	}
+	defer pool.Destroy()
+
+	for offset := int64(0); offset < int64(len(data)); offset += partSize {
+		end := offset + partSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		if err := pool.Write(key, data[offset:end], uint64(offset)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func init() {
 	ycsb.RegisterDBCreator("rados", radosCreator{})
 }
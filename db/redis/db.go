@@ -18,13 +18,28 @@ import (
 
 type redisClient interface {
 	Get(key string) *goredis.StringCmd
+	MGet(keys ...string) *goredis.SliceCmd
 	Scan(cursor uint64, match string, count int64) *goredis.ScanCmd
 	Set(key string, value interface{}, expiration time.Duration) *goredis.StatusCmd
 	Del(keys ...string) *goredis.IntCmd
+	HGetAll(key string) *goredis.StringStringMapCmd
+	HMGet(key string, fields ...string) *goredis.SliceCmd
+	HSet(key string, values ...interface{}) *goredis.IntCmd
+	ZAdd(key string, members ...*goredis.Z) *goredis.IntCmd
+	ZRangeByLex(key string, opt *goredis.ZRangeBy) *goredis.StringSliceCmd
+	ZRem(key string, members ...interface{}) *goredis.IntCmd
+	Eval(script string, keys []string, args ...interface{}) *goredis.Cmd
+	EvalSha(sha1 string, keys []string, args ...interface{}) *goredis.Cmd
+	ScriptExists(hashes ...string) *goredis.BoolSliceCmd
+	ScriptLoad(script string) *goredis.StringCmd
+	Pipeline() goredis.Pipeliner
 	FlushDB() *goredis.StatusCmd
 	Close() error
 }
 
+// ErrScanNotSupported is returned by Scan of the JSON records, whose keys aren't kept in order.
+var ErrScanNotSupported = fmt.Errorf("redis: scan is %w", ycsb.ErrNotSupported)
+
 type redis struct {
 	client redisClient
 	mode   string
@@ -47,7 +62,9 @@ func (r *redis) Read(ctx context.Context, table string, key string, fields []str
 
 	res, err := r.client.Get(table + "/" + key).Result()
 
-	if err != nil {
+	if err == goredis.Nil {
+		return nil, fmt.Errorf("key %w: %s/%s", ycsb.ErrNotFound, table, key)
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -56,13 +73,69 @@ func (r *redis) Read(ctx context.Context, table string, key string, fields []str
 		return nil, err
 	}
 
-	// TODO: filter by fields
+	return project(data, fields), nil
+}
+
+// project keeps the fields of the row, or all the fields if fields is empty.
+func project(data map[string][]byte, fields []string) map[string][]byte {
+	if len(fields) == 0 {
+		return data
+	}
+
+	m := make(map[string][]byte, len(fields))
+	for _, field := range fields {
+		if value, ok := data[field]; ok {
+			m[field] = value
+		}
+	}
+	return m
+}
+
+func (r *redis) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	rowKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		rowKeys = append(rowKeys, table+"/"+key)
+	}
+
+	var values []interface{}
+	if r.mode == "cluster" {
+		// The keys may be in different slots, so they can't be read by one MGET.
+		pipe := r.client.Pipeline()
+		cmds := make([]*goredis.StringCmd, 0, len(rowKeys))
+		for _, rowKey := range rowKeys {
+			cmds = append(cmds, pipe.Get(rowKey))
+		}
+		if _, err := pipe.Exec(); err != nil {
+			return nil, err
+		}
+		for _, cmd := range cmds {
+			values = append(values, cmd.Val())
+		}
+	} else {
+		var err error
+		if values, err = r.client.MGet(rowKeys...).Result(); err != nil {
+			return nil, err
+		}
+	}
+
+	rows := make([]map[string][]byte, 0, len(keys))
+	for i, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("key not found: %s", rowKeys[i])
+		}
 
-	return data, err
+		data := make(map[string][]byte, len(fields))
+		if err := json.Unmarshal([]byte(s), &data); err != nil {
+			return nil, err
+		}
+		rows = append(rows, project(data, fields))
+	}
+	return rows, nil
 }
 
 func (r *redis) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
-	return nil, fmt.Errorf("scan is not supported")
+	return nil, ErrScanNotSupported
 }
 
 func (r *redis) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
@@ -109,6 +182,46 @@ func (r *redis) Delete(ctx context.Context, table string, key string) error {
 	return r.client.Del(table + "/" + key).Err()
 }
 
+func (r *redis) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	pipe := r.client.Pipeline()
+	for i, key := range keys {
+		data, err := json.Marshal(values[i])
+		if err != nil {
+			pipe.Close()
+			return err
+		}
+		pipe.Set(table+"/"+key, string(data), 0)
+	}
+
+	_, err := pipe.Exec()
+	return err
+}
+
+func (r *redis) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	rows, err := r.BatchRead(ctx, table, keys, nil)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		for k, v := range values[i] {
+			row[k] = v
+		}
+	}
+	return r.BatchInsert(ctx, table, keys, rows)
+}
+
+func (r *redis) BatchDelete(ctx context.Context, table string, keys []string) error {
+	// The keys may be in different slots in the cluster mode, so they are deleted one by one.
+	pipe := r.client.Pipeline()
+	for _, key := range keys {
+		pipe.Del(table + "/" + key)
+	}
+
+	_, err := pipe.Exec()
+	return err
+}
+
 type redisCreator struct{}
 
 func (r redisCreator) Create(p *properties.Properties) (ycsb.DB, error) {
@@ -142,11 +255,19 @@ func (r redisCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	rds.mock, _ = strconv.ParseBool(s)
 	rds.mode = mode
 
-	return rds, nil
+	switch storage := p.GetString(redisStorage, "json"); storage {
+	case "json":
+		return rds, nil
+	case "hash":
+		return &hashRedis{rds}, nil
+	default:
+		return nil, fmt.Errorf("unknown %s %s", redisStorage, storage)
+	}
 }
 
 const (
 	redisMode                  = "redis.mode"
+	redisStorage               = "redis.storage"
 	redisNetwork               = "redis.network"
 	redisAddr                  = "redis.addr"
 	redisPassword              = "redis.password"
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v7"
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/dbtest"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+func newTestDB(t *testing.T, storage string) (ycsb.DB, *miniredis.Miniredis) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := properties.NewProperties()
+	p.Set(redisAddr, s.Addr())
+	p.Set(redisStorage, storage)
+	db, err := redisCreator{}.Create(p)
+	if err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+	return db, s
+}
+
+func TestJSON(t *testing.T) {
+	db, s := newTestDB(t, "json")
+	defer s.Close()
+
+	dbtest.TestCRUD(t, db)
+	dbtest.TestBatch(t, db)
+	if _, err := db.Scan(context.Background(), "t", "a", 10, nil); !errors.Is(err, ycsb.ErrNotSupported) {
+		t.Fatalf("want the unsupported scan, but got %v", err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	db, s := newTestDB(t, "hash")
+	defer s.Close()
+
+	dbtest.TestCRUD(t, db)
+	dbtest.TestBatch(t, db)
+
+	// An update only writes its fields.
+	if err := db.Insert(context.Background(), "t", "k2", dbtest.Row("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Update(context.Background(), "t", "k2", map[string][]byte{"field1": []byte("b")}); err != nil {
+		t.Fatal(err)
+	}
+	if v := s.HGet("t/k2", "field0"); v != "a" {
+		t.Fatalf("want field0 a, but got %s", v)
+	}
+
+	// An update doesn't create a missing record.
+	if err := db.Update(context.Background(), "t", "k3", dbtest.Row("a")); err != goredis.Nil {
+		t.Fatalf("want nil for updating a missing key, but got %v", err)
+	}
+	if err := db.(ycsb.BatchDB).BatchUpdate(context.Background(), "t", []string{"k2", "k3"}, []map[string][]byte{dbtest.Row("b"), dbtest.Row("b")}); err != goredis.Nil {
+		t.Fatalf("want nil for updating a missing key, but got %v", err)
+	}
+	if s.Exists("t/k3") {
+		t.Fatal("want no record created by updating a missing key")
+	}
+}
+
+func TestHashScan(t *testing.T) {
+	db, s := newTestDB(t, "hash")
+	defer s.Close()
+
+	dbtest.TestScan(t, db)
+}
@@ -0,0 +1,191 @@
+package redis
+
+import (
+	"context"
+
+	goredis "github.com/go-redis/redis/v7"
+)
+
+// hashRedis stores every record as a hash, so the fields are read and updated without
+// reading the whole record. The keys of a table are also added to the sorted set
+// "<table>:index" with the same score, which is scanned in the key order by ZRANGEBYLEX.
+type hashRedis struct {
+	*redis
+}
+
+// hashUpdate updates the fields of the record only if the record exists, otherwise it
+// returns nil, which is goredis.Nil, so an update doesn't create a partial record out of
+// the index.
+var hashUpdate = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+return redis.call('HSET', KEYS[1], unpack(ARGV))
+`)
+
+func indexKey(table string) string {
+	return table + ":index"
+}
+
+func hashValues(values map[string][]byte) []interface{} {
+	args := make([]interface{}, 0, 2*len(values))
+	for field, value := range values {
+		args = append(args, field, value)
+	}
+	return args
+}
+
+// hashReadCmd is the HGETALL or HMGET command to read a record.
+type hashReadCmd struct {
+	all    *goredis.StringStringMapCmd
+	fields []string
+	values *goredis.SliceCmd
+}
+
+// hashReader is the client or the pipeline to read a record.
+type hashReader interface {
+	HGetAll(key string) *goredis.StringStringMapCmd
+	HMGet(key string, fields ...string) *goredis.SliceCmd
+}
+
+func hashRead(c hashReader, rowKey string, fields []string) hashReadCmd {
+	if len(fields) == 0 {
+		return hashReadCmd{all: c.HGetAll(rowKey)}
+	}
+	return hashReadCmd{fields: fields, values: c.HMGet(rowKey, fields...)}
+}
+
+// result returns the record, or goredis.Nil if the record doesn't exist.
+func (c hashReadCmd) result() (map[string][]byte, error) {
+	if c.all != nil {
+		res, err := c.all.Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(res) == 0 {
+			return nil, goredis.Nil
+		}
+
+		m := make(map[string][]byte, len(res))
+		for field, value := range res {
+			m[field] = []byte(value)
+		}
+		return m, nil
+	}
+
+	res, err := c.values.Result()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string][]byte, len(c.fields))
+	for i, value := range res {
+		if s, ok := value.(string); ok {
+			m[c.fields[i]] = []byte(s)
+		}
+	}
+	if len(m) == 0 {
+		return nil, goredis.Nil
+	}
+	return m, nil
+}
+
+// read reads the records in one pipeline. If skipMissing is true, the missing records
+// are skipped, otherwise it returns goredis.Nil.
+func (r *hashRedis) read(table string, keys []string, fields []string, skipMissing bool) ([]map[string][]byte, error) {
+	pipe := r.client.Pipeline()
+	cmds := make([]hashReadCmd, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, hashRead(pipe, table+"/"+key, fields))
+	}
+	if _, err := pipe.Exec(); err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string][]byte, 0, len(keys))
+	for _, cmd := range cmds {
+		m, err := cmd.result()
+		if err == goredis.Nil && skipMissing {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		rows = append(rows, m)
+	}
+	return rows, nil
+}
+
+func (r *hashRedis) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	return hashRead(r.client, table+"/"+key, fields).result()
+}
+
+func (r *hashRedis) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	return r.read(table, keys, fields, false)
+}
+
+func (r *hashRedis) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
+	keys, err := r.client.ZRangeByLex(indexKey(table), &goredis.ZRangeBy{
+		Min:   "[" + startKey,
+		Max:   "+",
+		Count: int64(count),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	// The records may be deleted after their keys are read from the index.
+	return r.read(table, keys, fields, true)
+}
+
+func (r *hashRedis) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	return hashUpdate.Run(r.client, []string{table + "/" + key}, hashValues(values)...).Err()
+}
+
+func (r *hashRedis) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	// The script is sent with every update, because the pipeline can't fall back from
+	// EVALSHA when the script isn't loaded.
+	pipe := r.client.Pipeline()
+	for i, key := range keys {
+		hashUpdate.Eval(pipe, []string{table + "/" + key}, hashValues(values[i])...)
+	}
+
+	_, err := pipe.Exec()
+	return err
+}
+
+func (r *hashRedis) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	return r.BatchInsert(ctx, table, []string{key}, []map[string][]byte{values})
+}
+
+func (r *hashRedis) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	pipe := r.client.Pipeline()
+	members := make([]*goredis.Z, 0, len(keys))
+	for i, key := range keys {
+		pipe.HSet(table+"/"+key, hashValues(values[i])...)
+		members = append(members, &goredis.Z{Member: key})
+	}
+	pipe.ZAdd(indexKey(table), members...)
+
+	_, err := pipe.Exec()
+	return err
+}
+
+func (r *hashRedis) Delete(ctx context.Context, table string, key string) error {
+	return r.BatchDelete(ctx, table, []string{key})
+}
+
+func (r *hashRedis) BatchDelete(ctx context.Context, table string, keys []string) error {
+	pipe := r.client.Pipeline()
+	members := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		pipe.Del(table + "/" + key)
+		members = append(members, key)
+	}
+	pipe.ZRem(indexKey(table), members...)
+
+	_, err := pipe.Exec()
+	return err
+}
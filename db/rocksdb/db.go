@@ -16,6 +16,7 @@
 package rocksdb
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -196,22 +197,36 @@ func (db *rocksDB) Read(ctx context.Context, table string, key string, fields []
 }
 
 func (db *rocksDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
-	res := make([]map[string][]byte, count)
+	return db.scan(db.getRowKey(table, startKey), nil, count, fields)
+}
+
+func (db *rocksDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(db.getRowKey(table, startKey), db.getRowKey(table, endKey), limit, fields)
+}
+
+// scan reads at most count rows from rowStartKey, and stops before rowEndKey if it is not nil.
+func (db *rocksDB) scan(rowStartKey []byte, rowEndKey []byte, count int, fields []string) ([]map[string][]byte, error) {
+	res := make([]map[string][]byte, 0, count)
 	it := db.db.NewIterator(db.readOpts)
 	defer it.Close()
 
-	rowStartKey := db.getRowKey(table, startKey)
-
 	it.Seek(rowStartKey)
-	i := 0
-	for it = it; it.Valid() && i < count; it.Next() {
+	for ; it.Valid() && len(res) < count; it.Next() {
+		if rowEndKey != nil {
+			key := it.Key()
+			end := bytes.Compare(key.Data(), rowEndKey) >= 0
+			key.Free()
+			if end {
+				break
+			}
+		}
+
 		value := it.Value()
 		m, err := db.r.Decode(cloneValue(value), fields)
 		if err != nil {
 			return nil, err
 		}
-		res[i] = m
-		i++
+		res = append(res, m)
 	}
 
 	if err := it.Err(); err != nil {
@@ -263,6 +278,35 @@ func (db *rocksDB) Delete(ctx context.Context, table string, key string) error {
 	return db.db.Delete(db.writeOpts, rowKey)
 }
 
+// DeleteRange deletes the rows in [startKey, endKey) one by one in a batch, because the
+// write batch of gorocksdb has no DeleteRange.
+func (db *rocksDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	rowEndKey := db.getRowKey(table, endKey)
+	wb := gorocksdb.NewWriteBatch()
+	defer wb.Destroy()
+
+	it := db.db.NewIterator(db.readOpts)
+	defer it.Close()
+
+	// The write batch copies the keys, so the iterator can move on.
+	for it.Seek(db.getRowKey(table, startKey)); it.Valid(); it.Next() {
+		key := it.Key()
+		end := bytes.Compare(key.Data(), rowEndKey) >= 0
+		if !end {
+			wb.Delete(key.Data())
+		}
+		key.Free()
+		if end {
+			break
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return db.db.Write(db.writeOpts, wb)
+}
+
 func init() {
 	ycsb.RegisterDBCreator("rocksdb", rocksDBCreator{})
 }
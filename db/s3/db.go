@@ -11,6 +11,7 @@ import (
 	"github.com/journeymidnight/aws-sdk-go/service/s3"
 	"github.com/magiconair/properties"
 	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
 	"github.com/pingcap/go-ycsb/pkg/ycsb"
 	"io/ioutil"
 	"math/rand"
@@ -52,6 +53,7 @@ type s3Options struct {
 	validHead       bool
 	randomKey       bool
 	randomBucket    bool
+	valueGenerator  util.ValueGenerator
 }
 
 type s3Client struct {
@@ -65,7 +67,10 @@ type s3State struct {
 }
 
 func (s s3Creator) Create(p *properties.Properties) (ycsb.DB, error) {
-	opt := getOptions(p)
+	opt, err := getOptions(p)
+	if err != nil {
+		return nil, err
+	}
 	if opt.randomBucket {
 		client := newS3(opt)
 		for i := 0; i < len(BucketPrefix); i++ {
@@ -80,7 +85,7 @@ func (s s3Creator) Create(p *properties.Properties) (ycsb.DB, error) {
 	}, nil
 }
 
-func getOptions(p *properties.Properties) s3Options {
+func getOptions(p *properties.Properties) (s3Options, error) {
 	s3Endpoint := p.GetString(endpoint, "s3.test.com")
 	s3AccessKey := p.GetString(accessKey, "hehehehe")
 	s3SecretKey := p.GetString(secretKey, "hehehehe")
@@ -90,12 +95,19 @@ func getOptions(p *properties.Properties) s3Options {
 	s3DisableMd5 := p.GetBool(disableMd5Check, false)
 	s3DataLength, err := humanize.ParseBytes(p.GetString(dataLength, "4KiB"))
 	if err != nil {
-		panic(err)
+		return s3Options{}, err
 	}
 	s3OnlyHead := p.GetBool(onlyHead, false)
 	random := p.GetBool(prop.RandomKey, false)
 	randomBucket := p.GetBool(prop.RandomBucket, false)
 	rand.Seed(time.Now().UnixNano())
+	var valueGenerator util.ValueGenerator
+	if _, ok := p.Get(prop.ValueGenerator); ok {
+		valueGenerator, err = util.NewValueGenerator(p)
+		if err != nil {
+			return s3Options{}, err
+		}
+	}
 
 	return s3Options{
 		endpoint:        s3Endpoint,
@@ -109,7 +121,8 @@ func getOptions(p *properties.Properties) s3Options {
 		validHead:       s3OnlyHead,
 		randomKey:       random,
 		randomBucket:    randomBucket,
-	}
+		valueGenerator:  valueGenerator,
+	}, nil
 }
 
 // Close closes the database layer.
@@ -152,8 +165,12 @@ func newS3(opts s3Options) *s3.S3 {
 // The Returned context will be passed to the following usage.
 func (c *s3Client) InitThread(ctx context.Context, threadID int, threadCount int) context.Context {
 	mockData := make([]byte, c.p.dataLength)
-	for i := 0; i < len(mockData); i++ {
-		mockData[i] = uint8(i % 255)
+	if c.p.valueGenerator != nil {
+		c.p.valueGenerator.Fill(rand.New(rand.NewSource(time.Now().UnixNano())), mockData)
+	} else {
+		for i := 0; i < len(mockData); i++ {
+			mockData[i] = uint8(i % 255)
+		}
 	}
 	client := newS3(c.p)
 	state := &s3State{
@@ -341,6 +358,126 @@ func (c *s3Client) Delete(ctx context.Context, table string, key string) error {
 	return nil
 }
 
+// PutObject implements the ObjectDB PutObject interface. The configured bucket is used.
+func (c *s3Client) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	state := ctx.Value(stateKey).(*s3State)
+	input := &s3.PutObjectInput{
+		Bucket:       aws.String(state.b),
+		Key:          aws.String(key),
+		StorageClass: aws.String(c.p.storageClass),
+		Body:         bytes.NewReader(data),
+	}
+	_, err := state.c.PutObjectWithContext(ctx, input)
+	return err
+}
+
+// GetObject implements the ObjectDB GetObject interface. The configured bucket is used.
+func (c *s3Client) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error) {
+	state := ctx.Value(stateKey).(*s3State)
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(state.b),
+		Key:    aws.String(key),
+	}
+	if length > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+	object, err := state.c.GetObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Body.Close()
+	return ioutil.ReadAll(object.Body)
+}
+
+// HeadObject implements the ObjectDB HeadObject interface. The configured bucket is used.
+func (c *s3Client) HeadObject(ctx context.Context, bucket string, key string) (int64, error) {
+	state := ctx.Value(stateKey).(*s3State)
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(state.b),
+		Key:    aws.String(key),
+	}
+	out, err := state.c.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	return aws.Int64Value(out.ContentLength), nil
+}
+
+// ListObjects implements the ObjectDB ListObjects interface. The configured bucket is used.
+func (c *s3Client) ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error) {
+	state := ctx.Value(stateKey).(*s3State)
+	input := &s3.ListObjectsInput{
+		Bucket:  aws.String(state.b),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int64(int64(count)),
+	}
+	out, err := state.c.ListObjectsWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(out.Contents))
+	for _, v := range out.Contents {
+		keys = append(keys, aws.StringValue(v.Key))
+	}
+	return keys, nil
+}
+
+// DeleteObject implements the ObjectDB DeleteObject interface. The configured bucket is used.
+func (c *s3Client) DeleteObject(ctx context.Context, bucket string, key string) error {
+	return c.Delete(ctx, bucket, key)
+}
+
+// MultipartUpload implements the ObjectDB MultipartUpload interface. The configured bucket is used.
+func (c *s3Client) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error {
+	state := ctx.Value(stateKey).(*s3State)
+	client := state.c
+	upload, err := client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:       aws.String(state.b),
+		Key:          aws.String(key),
+		StorageClass: aws.String(c.p.storageClass),
+	})
+	if err != nil {
+		return err
+	}
+
+	var parts []*s3.CompletedPart
+	for i := int64(0); i*partSize < int64(len(data)); i++ {
+		end := (i + 1) * partSize
+		if end > int64(len(data)) {
+			end = int64(len(data))
+		}
+		out, err := client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(state.b),
+			Key:        aws.String(key),
+			UploadId:   upload.UploadId,
+			PartNumber: aws.Int64(i + 1),
+			Body:       bytes.NewReader(data[i*partSize : end]),
+		})
+		if err != nil {
+			client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(state.b),
+				Key:      aws.String(key),
+				UploadId: upload.UploadId,
+			})
+			return err
+		}
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       out.ETag,
+			PartNumber: aws.Int64(i + 1),
+		})
+	}
+
+	_, err = client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(state.b),
+		Key:             aws.String(key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
+}
+
 func init() {
 	ycsb.RegisterDBCreator("s3", s3Creator{})
 }
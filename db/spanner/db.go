@@ -101,6 +101,9 @@ func (c spannerCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	if err = d.createTable(ctx, adminClient, dbName); err != nil {
 		return nil, err
 	}
+	if err = d.createMetadataTable(ctx, adminClient, dbName); err != nil {
+		return nil, err
+	}
 
 	return d, nil
 }
@@ -161,8 +164,8 @@ func (db *spannerDB) tableExisted(ctx context.Context, table string) (bool, erro
 
 func (db *spannerDB) createTable(ctx context.Context, adminClient *database.DatabaseAdminClient, dbName string) error {
 	tableName := db.p.GetString(prop.TableName, prop.TableNameDefault)
-	fieldCount := db.p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
 	fieldLength := db.p.GetInt64(prop.FieldLength, prop.FieldLengthDefault)
+	schema := util.LoadSchema(db.p)
 
 	existed, err := db.tableExisted(ctx, tableName)
 	if err != nil {
@@ -194,8 +197,16 @@ func (db *spannerDB) createTable(ctx context.Context, adminClient *database.Data
 	s := fmt.Sprintf("CREATE TABLE  %s (YCSB_KEY STRING(%d)", tableName, fieldLength)
 	buf.WriteString(s)
 
-	for i := int64(0); i < fieldCount; i++ {
-		buf.WriteString(fmt.Sprintf(", FIELD%d STRING(%d)", i, fieldLength))
+	// The int and timestamp values are stored as text.
+	for _, f := range schema.Fields {
+		switch f.Type {
+		case util.FieldTypeBytes:
+			buf.WriteString(fmt.Sprintf(", %s BYTES(%d)", f.Name, f.Length))
+		case util.FieldTypeString:
+			buf.WriteString(fmt.Sprintf(", %s STRING(%d)", f.Name, f.Length))
+		default:
+			buf.WriteString(fmt.Sprintf(", %s STRING(MAX)", f.Name))
+		}
 	}
 
 	buf.WriteString(") PRIMARY KEY (YCSB_KEY)")
@@ -217,6 +228,43 @@ func (db *spannerDB) createTable(ctx context.Context, adminClient *database.Data
 	return nil
 }
 
+// createMetadataTable creates the table of the load metadata, whose field keeps the
+// metadata in JSON.
+func (db *spannerDB) createMetadataTable(ctx context.Context, adminClient *database.DatabaseAdminClient, dbName string) error {
+	tableName := util.MetadataTable(db.p)
+	f := util.LoadSchema(db.p).MetadataField()
+	if len(tableName) == 0 || f == nil {
+		return nil
+	}
+
+	existed, err := db.tableExisted(ctx, tableName)
+	if err != nil {
+		return err
+	}
+
+	var stmts []string
+	if db.p.GetBool(prop.DropData, prop.DropDataDefault) && existed {
+		stmts = append(stmts, fmt.Sprintf("DROP TABLE %s", tableName))
+	} else if existed {
+		return nil
+	}
+
+	colType := "STRING(MAX)"
+	if f.Type == util.FieldTypeBytes {
+		colType = "BYTES(MAX)"
+	}
+	stmts = append(stmts, fmt.Sprintf("CREATE TABLE %s (YCSB_KEY STRING(MAX), %s %s) PRIMARY KEY (YCSB_KEY)", tableName, f.Name, colType))
+
+	op, err := adminClient.UpdateDatabaseDdl(ctx, &adminpb.UpdateDatabaseDdlRequest{
+		Database:   dbName,
+		Statements: stmts,
+	})
+	if err != nil {
+		return err
+	}
+	return op.Wait(ctx)
+}
+
 func (db *spannerDB) Close() error {
 	if db.client == nil {
 		return nil
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/pingcap/go-ycsb/pkg/prop"
@@ -37,6 +38,7 @@ const (
 	sqliteMode        = "sqlite.mode"
 	sqliteJournalMode = "sqlite.journalmode"
 	sqliteCache       = "sqlite.cache"
+	sqliteBulkLoad    = "sqlite.bulk_load"
 )
 
 type sqliteCreator struct {
@@ -46,6 +48,8 @@ type sqliteDB struct {
 	p       *properties.Properties
 	db      *sql.DB
 	verbose bool
+	// bulkLoad inserts the batches in a transaction instead of a multi-row INSERT.
+	bulkLoad bool
 
 	bufPool *util.BufPool
 }
@@ -54,6 +58,11 @@ func (c sqliteCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	d := new(sqliteDB)
 	d.p = p
 
+	// The binary keys may be invalid strings or contain NUL for the VARCHAR key.
+	if strings.ToLower(p.GetString(prop.KeyFormat, prop.KeyFormatDefault)) == "binary" {
+		return nil, fmt.Errorf("sqlite doesn't support keyformat=binary")
+	}
+
 	dbPath := p.GetString(sqliteDBPath, "/tmp/sqlite.db")
 
 	if p.GetBool(prop.DropData, prop.DropDataDefault) {
@@ -78,6 +87,7 @@ func (c sqliteCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 	db.SetMaxOpenConns(1)
 
 	d.verbose = p.GetBool(prop.Verbose, prop.VerboseDefault)
+	d.bulkLoad = p.GetBool(sqliteBulkLoad, false)
 	d.db = db
 
 	d.bufPool = util.NewBufPool()
@@ -92,15 +102,14 @@ func (c sqliteCreator) Create(p *properties.Properties) (ycsb.DB, error) {
 func (db *sqliteDB) createTable() error {
 	tableName := db.p.GetString(prop.TableName, prop.TableNameDefault)
 
-	fieldCount := db.p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
-	fieldLength := db.p.GetInt64(prop.FieldLength, prop.FieldLengthDefault)
+	schema := util.LoadSchema(db.p)
 
 	buf := new(bytes.Buffer)
 	s := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (YCSB_KEY VARCHAR(64) PRIMARY KEY", tableName)
 	buf.WriteString(s)
 
-	for i := int64(0); i < fieldCount; i++ {
-		buf.WriteString(fmt.Sprintf(", FIELD%d VARCHAR(%d)", i, fieldLength))
+	for _, f := range schema.Fields {
+		buf.WriteString(fmt.Sprintf(", %s %s", f.Name, columnType(f)))
 	}
 
 	buf.WriteString(");")
@@ -109,10 +118,43 @@ func (db *sqliteDB) createTable() error {
 		fmt.Println(buf.String())
 	}
 
-	_, err := db.db.Exec(buf.String())
+	if _, err := db.db.Exec(buf.String()); err != nil {
+		return err
+	}
+	return db.createMetadataTable(schema)
+}
+
+// createMetadataTable creates the table of the load metadata, whose field keeps the
+// metadata in JSON.
+func (db *sqliteDB) createMetadataTable(schema *util.Schema) error {
+	tableName := util.MetadataTable(db.p)
+	f := schema.MetadataField()
+	if len(tableName) == 0 || f == nil {
+		return nil
+	}
+
+	colType := "TEXT"
+	if f.Type == util.FieldTypeBytes {
+		colType = "BLOB"
+	}
+	_, err := db.db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (YCSB_KEY VARCHAR(64) PRIMARY KEY, %s %s);", tableName, f.Name, colType))
 	return err
 }
 
+// columnType returns the column type of the field in the schema.
+func columnType(f util.SchemaField) string {
+	switch f.Type {
+	case util.FieldTypeBytes:
+		return "BLOB"
+	case util.FieldTypeInt:
+		return "INTEGER"
+	case util.FieldTypeTimestamp:
+		return "TIMESTAMP"
+	default:
+		return fmt.Sprintf("VARCHAR(%d)", f.Length)
+	}
+}
+
 func (db *sqliteDB) Close() error {
 	if db.db == nil {
 		return nil
@@ -186,6 +228,57 @@ func (db *sqliteDB) Read(ctx context.Context, table string, key string, fields [
 	return rows[0], nil
 }
 
+// placeholders returns n comma-separated placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// BatchRead reads the records by the queries of the keys, each with at most maxParams keys.
+// Like Read, the records of the missing keys are nil.
+func (db *sqliteDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	byKey := make(map[string]map[string][]byte, len(keys))
+	for start := 0; start < len(keys); start += maxParams {
+		end := start + maxParams
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		var query string
+		if len(fields) == 0 {
+			query = fmt.Sprintf(`SELECT * FROM %s WHERE YCSB_KEY IN (%s)`, table, placeholders(end-start))
+		} else {
+			query = fmt.Sprintf(`SELECT YCSB_KEY, %s FROM %s WHERE YCSB_KEY IN (%s)`, strings.Join(fields, ","), table, placeholders(end-start))
+		}
+
+		rows, err := db.queryRows(ctx, query, end-start, keyArgs(keys[start:end])...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			byKey[string(row["YCSB_KEY"])] = row
+			if len(fields) > 0 {
+				delete(row, "YCSB_KEY")
+			}
+		}
+	}
+
+	res := make([]map[string][]byte, len(keys))
+	for i, key := range keys {
+		res[i] = byKey[key]
+	}
+	return res, nil
+}
+
+// keyArgs returns the keys as the arguments of a statement.
+func keyArgs(keys []string) []interface{} {
+	args := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return args
+}
+
 func (db *sqliteDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
 	var query string
 	if len(fields) == 0 {
@@ -199,12 +292,25 @@ func (db *sqliteDB) Scan(ctx context.Context, table string, startKey string, cou
 	return rows, err
 }
 
+func (db *sqliteDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE YCSB_KEY >= ? AND YCSB_KEY < ? LIMIT ?`, table)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE YCSB_KEY >= ? AND YCSB_KEY < ? LIMIT ?`, strings.Join(fields, ","), table)
+	}
+
+	rows, err := db.queryRows(ctx, query, limit, startKey, endKey, limit)
+
+	return rows, err
+}
+
 func (db *sqliteDB) execQuery(ctx context.Context, query string, args ...interface{}) error {
 	if db.verbose {
 		fmt.Printf("%s %v\n", query, args)
 	}
 
-	_, err := db.db.ExecContext(ctx, query, args)
+	_, err := db.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -212,7 +318,7 @@ func (db *sqliteDB) execQuery(ctx context.Context, query string, args ...interfa
 	return err
 }
 
-func (db *sqliteDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+func (db *sqliteDB) updateQuery(table string, key string, values map[string][]byte) (string, []interface{}) {
 	buf := db.bufPool.Get()
 	defer db.bufPool.Put(buf)
 
@@ -237,13 +343,37 @@ func (db *sqliteDB) Update(ctx context.Context, table string, key string, values
 
 	args = append(args, key)
 
-	return db.execQuery(ctx, buf.String(), args...)
+	return buf.String(), args
 }
 
-func (db *sqliteDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
-	args := make([]interface{}, 0, 1+len(values))
-	args = append(args, key)
+func (db *sqliteDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	query, args := db.updateQuery(table, key, values)
+	return db.execQuery(ctx, query, args...)
+}
+
+// BatchUpdate updates the records one by one in a transaction.
+func (db *sqliteDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
 
+	for i, key := range keys {
+		query, args := db.updateQuery(table, key, values[i])
+		if db.verbose {
+			fmt.Printf("%s %v\n", query, args)
+		}
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertQuery builds the INSERT of rowCount rows of the fields.
+func (db *sqliteDB) insertQuery(table string, fields []string, rowCount int) string {
 	buf := db.bufPool.Get()
 	defer db.bufPool.Put(buf)
 
@@ -251,21 +381,118 @@ func (db *sqliteDB) Insert(ctx context.Context, table string, key string, values
 	buf.WriteString(table)
 	buf.WriteString(" (YCSB_KEY")
 
-	pairs := util.NewFieldPairs(values)
-	for _, p := range pairs {
-		args = append(args, p.Value)
+	for _, field := range fields {
 		buf.WriteString(" ,")
-		buf.WriteString(p.Field)
+		buf.WriteString(field)
 	}
-	buf.WriteString(") VALUES (?")
+	buf.WriteString(") VALUES ")
 
-	for i := 0; i < len(pairs); i++ {
-		buf.WriteString(" ,?")
+	for i := 0; i < rowCount; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("(?")
+		for j := 0; j < len(fields); j++ {
+			buf.WriteString(" ,?")
+		}
+		buf.WriteByte(')')
 	}
 
-	buf.WriteByte(')')
+	return buf.String()
+}
 
-	return db.execQuery(ctx, buf.String(), args...)
+// maxParams is the max number of the parameters of a statement in SQLite.
+const maxParams = 999
+
+// insertFields returns the sorted fields of all the values, and the records of a batch
+// can have different fields.
+func insertFields(values ...map[string][]byte) []string {
+	seen := make(map[string]struct{})
+	var fields []string
+	for _, m := range values {
+		for field := range m {
+			if _, ok := seen[field]; !ok {
+				seen[field] = struct{}{}
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// insertArgs appends the key and the values of the fields, and the missing fields are NULL.
+func insertArgs(args []interface{}, key string, fields []string, values map[string][]byte) []interface{} {
+	args = append(args, key)
+	for _, field := range fields {
+		if v, ok := values[field]; ok {
+			args = append(args, v)
+		} else {
+			args = append(args, nil)
+		}
+	}
+	return args
+}
+
+// batchInsert inserts the records by the multi-row INSERTs, each with at most
+// maxParams parameters.
+func (db *sqliteDB) batchInsert(ctx context.Context, table string, keys []string, fields []string, values []map[string][]byte) error {
+	rowCount := maxParams / (1 + len(fields))
+	for start := 0; start < len(keys); start += rowCount {
+		end := start + rowCount
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		args := make([]interface{}, 0, (end-start)*(1+len(fields)))
+		for i := start; i < end; i++ {
+			args = insertArgs(args, keys[i], fields, values[i])
+		}
+		if err := db.execQuery(ctx, db.insertQuery(table, fields, end-start), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *sqliteDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	fields := insertFields(values)
+	args := insertArgs(make([]interface{}, 0, 1+len(fields)), key, fields, values)
+
+	return db.execQuery(ctx, db.insertQuery(table, fields, 1), args...)
+}
+
+// BatchInsert inserts the records by the multi-row INSERTs, or with sqlite.bulk_load, by
+// the single-row INSERTs in a transaction. The fields of the records are the fields of all
+// the records.
+func (db *sqliteDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	fields := insertFields(values...)
+	if !db.bulkLoad {
+		return db.batchInsert(ctx, table, keys, fields, values)
+	}
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, db.insertQuery(table, fields, 1))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	args := make([]interface{}, 0, 1+len(fields))
+	for i, key := range keys {
+		args = insertArgs(args[:0], key, fields, values[i])
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (db *sqliteDB) Delete(ctx context.Context, table string, key string) error {
@@ -274,6 +501,72 @@ func (db *sqliteDB) Delete(ctx context.Context, table string, key string) error
 	return db.execQuery(ctx, query, key)
 }
 
+// BatchDelete deletes the records by the DELETEs of the keys, each with at most maxParams
+// keys.
+func (db *sqliteDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	for start := 0; start < len(keys); start += maxParams {
+		end := start + maxParams
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		query := fmt.Sprintf(`DELETE FROM %s WHERE YCSB_KEY IN (%s)`, table, placeholders(end-start))
+		if err := db.execQuery(ctx, query, keyArgs(keys[start:end])...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *sqliteDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE YCSB_KEY >= ? AND YCSB_KEY < ?`, table)
+
+	return db.execQuery(ctx, query, startKey, endKey)
+}
+
+func (db *sqliteDB) CreateIndex(ctx context.Context, table string, field string) error {
+	query := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_%s ON %s (%s)`, table, field, table, field)
+	if db.verbose {
+		fmt.Println(query)
+	}
+
+	_, err := db.db.ExecContext(ctx, query)
+	return err
+}
+
+func (db *sqliteDB) Query(ctx context.Context, table string, field string, value []byte, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE %s = ?`, table, field)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE %s = ?`, strings.Join(fields, ","), table, field)
+	}
+
+	return db.queryRows(ctx, query, 1, value)
+}
+
+func (db *sqliteDB) RangeQuery(ctx context.Context, table string, field string, start []byte, count int, fields []string) ([]map[string][]byte, error) {
+	var query string
+	if len(fields) == 0 {
+		query = fmt.Sprintf(`SELECT * FROM %s WHERE %s >= ? ORDER BY %s LIMIT ?`, table, field, field)
+	} else {
+		query = fmt.Sprintf(`SELECT %s FROM %s WHERE %s >= ? ORDER BY %s LIMIT ?`, strings.Join(fields, ","), table, field, field)
+	}
+
+	return db.queryRows(ctx, query, count, start, count)
+}
+
+func (db *sqliteDB) Count(ctx context.Context, table string, field string, start []byte, end []byte) (int64, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s BETWEEN ? AND ?`, table, field)
+	if db.verbose {
+		fmt.Printf("%s %v\n", query, []interface{}{start, end})
+	}
+
+	var n int64
+	err := db.db.QueryRowContext(ctx, query, start, end).Scan(&n)
+	return n, err
+}
+
 func init() {
 	ycsb.RegisterDBCreator("sqlite", sqliteCreator{})
 }
@@ -0,0 +1,245 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+func newTestDB(t *testing.T, bulkLoad bool) (ycsb.DB, context.Context, func()) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := properties.NewProperties()
+	p.Set(sqliteDBPath, path.Join(dir, "sqlite.db"))
+	p.Set("fieldcount", "2")
+	if bulkLoad {
+		p.Set(sqliteBulkLoad, "true")
+	}
+	db, err := sqliteCreator{}.Create(p)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	ctx := db.InitThread(context.Background(), 0, 1)
+	return db, ctx, func() {
+		db.CleanupThread(ctx)
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func row(v string) map[string][]byte {
+	return map[string][]byte{"field0": []byte(v), "field1": []byte(v + v)}
+}
+
+func testBatch(t *testing.T, bulkLoad bool) {
+	db, ctx, clean := newTestDB(t, bulkLoad)
+	defer clean()
+	bdb := db.(ycsb.BatchDB)
+
+	keys := []string{"a", "b", "c"}
+	if err := bdb.BatchInsert(ctx, "usertable", keys, []map[string][]byte{row("a"), row("b"), row("c")}); err != nil {
+		t.Fatal(err)
+	}
+	// The existing keys are ignored.
+	if err := bdb.BatchInsert(ctx, "usertable", keys[:2], []map[string][]byte{row("x"), row("y")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bdb.BatchUpdate(ctx, "usertable", keys[:2], []map[string][]byte{{"field0": []byte("x")}, {"field0": []byte("y")}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := bdb.BatchRead(ctx, "usertable", []string{"c", "b", "a"}, []string{"field0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []map[string][]byte{{"field0": []byte("c")}, {"field0": []byte("y")}, {"field0": []byte("x")}}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+
+	if err = bdb.BatchDelete(ctx, "usertable", keys[1:]); err != nil {
+		t.Fatal(err)
+	}
+	rows, err = bdb.BatchRead(ctx, "usertable", keys, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string][]byte{{"YCSB_KEY": []byte("a"), "field0": []byte("x"), "field1": []byte("aa")}, nil, nil}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	testBatch(t, false)
+}
+
+func TestBulkLoad(t *testing.T) {
+	testBatch(t, true)
+}
+
+func TestLargeBatch(t *testing.T) {
+	db, ctx, clean := newTestDB(t, false)
+	defer clean()
+	bdb := db.(ycsb.BatchDB)
+
+	// The batch has more parameters than a statement can have, and only the first record
+	// doesn't have field1.
+	keys := make([]string, 0, 20000)
+	values := make([]map[string][]byte, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		key := fmt.Sprintf("user%05d", i)
+		keys = append(keys, key)
+		values = append(values, row(key))
+	}
+	delete(values[0], "field1")
+	if err := bdb.BatchInsert(ctx, "usertable", keys, values); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := bdb.BatchRead(ctx, "usertable", []string{keys[0], keys[19999]}, []string{"field0", "field1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []map[string][]byte{{"field0": []byte(keys[0]), "field1": nil}, row(keys[19999])}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+
+	// The batch reads and deletes have more keys than a statement can have.
+	rows, err = bdb.BatchRead(ctx, "usertable", keys[1:], []string{"field0", "field1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(keys)-1 {
+		t.Fatalf("want %d rows, but got %d", len(keys)-1, len(rows))
+	}
+	for i, r := range rows {
+		if want := row(keys[i+1]); !reflect.DeepEqual(r, want) {
+			t.Fatalf("want %q, but got %q", want, r)
+		}
+	}
+
+	if err := bdb.BatchDelete(ctx, "usertable", keys[:19999]); err != nil {
+		t.Fatal(err)
+	}
+	rows, err = bdb.BatchRead(ctx, "usertable", []string{keys[0], keys[1000], keys[19998], keys[19999]}, []string{"field0", "field1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []map[string][]byte{nil, nil, nil, row(keys[19999])}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	db, ctx, clean := newTestDB(t, false)
+	defer clean()
+	qdb := db.(ycsb.QueryDB)
+
+	// The field0 values are in the reverse order of the keys.
+	for i := 0; i < 20; i++ {
+		if err := db.Insert(ctx, "usertable", fmt.Sprintf("user%03d", i), row(fmt.Sprintf("v%03d", 19-i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := qdb.CreateIndex(ctx, "usertable", "field0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var name string
+	if err := db.(*sqliteDB).db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'index' AND sql IS NOT NULL AND tbl_name = 'usertable'`).Scan(&name); err != nil || name != "idx_usertable_field0" {
+		t.Fatalf("want the index idx_usertable_field0, but got %s, %v", name, err)
+	}
+
+	rows, err := qdb.Query(ctx, "usertable", "field0", []byte("v005"), []string{"field1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []map[string][]byte{{"field1": []byte("v005v005")}}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+
+	rows, err = qdb.RangeQuery(ctx, "usertable", "field0", []byte("v010"), 3, []string{"field0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []map[string][]byte{{"field0": []byte("v010")}, {"field0": []byte("v011")}, {"field0": []byte("v012")}}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+
+	if n, err := qdb.Count(ctx, "usertable", "field0", []byte("v005"), []byte("v009")); err != nil || n != 5 {
+		t.Fatalf("want the count 5, but got %d, %v", n, err)
+	}
+}
+
+func TestRange(t *testing.T) {
+	db, ctx, clean := newTestDB(t, false)
+	defer clean()
+	rdb := db.(ycsb.RangeDB)
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("user%03d", i)
+		if err := db.Insert(ctx, "usertable", key, row(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := rdb.ScanRange(ctx, "usertable", "user005", "user010", 100, []string{"field0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || string(rows[0]["field0"]) != "user005" || string(rows[4]["field0"]) != "user009" {
+		t.Fatalf("want the rows of user005 to user009, but got %q", rows)
+	}
+	if rows, err = rdb.ScanRange(ctx, "usertable", "user005", "user010", 2, nil); err != nil || len(rows) != 2 {
+		t.Fatalf("want 2 rows by the limit, but got %d, %v", len(rows), err)
+	}
+
+	if err = rdb.DeleteRange(ctx, "usertable", "user005", "user010"); err != nil {
+		t.Fatal(err)
+	}
+	if rows, err = rdb.ScanRange(ctx, "usertable", "user000", "user020", 100, nil); err != nil || len(rows) != 15 {
+		t.Fatalf("want 15 rows after the range delete, but got %d, %v", len(rows), err)
+	}
+}
+
+func TestBinaryKeyFormat(t *testing.T) {
+	p := properties.NewProperties()
+	p.Set(prop.KeyFormat, "binary")
+	if _, err := (sqliteCreator{}).Create(p); err == nil {
+		t.Fatal("want an error for the binary keys")
+	}
+}
+
+func TestMetadataTable(t *testing.T) {
+	db, ctx, clean := newTestDB(t, false)
+	defer clean()
+
+	// The missing metadata is read as no values, and the metadata may be longer than the
+	// fields.
+	if row, err := db.Read(ctx, "usertable_metadata", "core/a", []string{"field0"}); err != nil || len(row) != 0 {
+		t.Fatalf("want no metadata, but got %q %v", row, err)
+	}
+	data := []byte(strings.Repeat("m", 1000))
+	if err := db.Insert(ctx, "usertable_metadata", "core/a", map[string][]byte{"field0": data}); err != nil {
+		t.Fatal(err)
+	}
+	if row, err := db.Read(ctx, "usertable_metadata", "core/a", []string{"field0"}); err != nil || !bytes.Equal(row["field0"], data) {
+		t.Fatalf("want the metadata of 1000 bytes, but got %d bytes %v", len(row["field0"]), err)
+	}
+}
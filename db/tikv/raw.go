@@ -95,7 +95,15 @@ func (db *rawDB) BatchRead(ctx context.Context, table string, keys []string, fie
 }
 
 func (db *rawDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
-	_, rows, err := db.db.Scan(db.getRowKey(table, startKey), nil, count)
+	return db.scan(db.getRowKey(table, startKey), nil, count, fields)
+}
+
+func (db *rawDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	return db.scan(db.getRowKey(table, startKey), db.getRowKey(table, endKey), limit, fields)
+}
+
+func (db *rawDB) scan(startKey []byte, endKey []byte, count int, fields []string) ([]map[string][]byte, error) {
+	_, rows, err := db.db.Scan(startKey, endKey, count)
 	if err != nil {
 		return nil, err
 	}
@@ -189,3 +197,7 @@ func (db *rawDB) BatchDelete(ctx context.Context, table string, keys []string) e
 	}
 	return db.db.BatchDelete(rowKeys)
 }
+
+func (db *rawDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	return db.db.DeleteRange(db.getRowKey(table, startKey), db.getRowKey(table, endKey))
+}
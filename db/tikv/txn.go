@@ -36,11 +36,14 @@ type txnDB struct {
 	bufPool   *util.BufPool
 	random    bool
 	followyig bool
-	keySize   int
 	valSize   int
 }
 
 func createTxnDB(p *properties.Properties, conf config.Config) (ycsb.DB, error) {
+	if _, ok := p.Get(prop.DeprecatedKeyLength); ok {
+		return nil, fmt.Errorf("%s is removed, use %s to set the length of the keys", prop.DeprecatedKeyLength, prop.KeyLength)
+	}
+
 	pdAddr := p.GetString(tikvPD, "127.0.0.1:2379")
 	db, err := txnkv.NewClient(strings.Split(pdAddr, ","), conf)
 	if err != nil {
@@ -51,7 +54,6 @@ func createTxnDB(p *properties.Properties, conf config.Config) (ycsb.DB, error)
 	rand.Seed(time.Now().UnixNano())
 	random := p.GetBool(prop.RandomKey, false)
 	follow := p.GetBool(prop.Mock, false)
-	kenLen := p.GetInt(prop.KeyLength, 0)
 	valLen := p.GetInt(prop.ValueLength, 0)
 	return &txnDB{
 		db:        db,
@@ -59,7 +61,6 @@ func createTxnDB(p *properties.Properties, conf config.Config) (ycsb.DB, error)
 		bufPool:   bufPool,
 		random:    random,
 		followyig: follow,
-		keySize:   kenLen,
 		valSize:   valLen,
 	}, nil
 
@@ -248,9 +249,6 @@ func (db *txnDB) Insert(ctx context.Context, table string, key string, values ma
 	} else {
 		insertKey = db.getRowKey(table, key)
 	}
-	if db.keySize > 0 && len(key) > db.keySize{
-		insertKey = []byte(strconv.Itoa(rand.Intn(1000)) + key[len(key) - db.keySize:])
-	}
 	if db.valSize > 0 && len(rowData) > db.valSize {
 		rowData = rowData[:db.valSize]
 	}
@@ -0,0 +1,106 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// checkpoint is the progress of the load saved in the checkpoint file. All the keys
+// before Next are inserted, and the keys in [Next, End) may be not.
+type checkpoint struct {
+	Next int64 `json:"next"`
+	End  int64 `json:"end"`
+}
+
+func readCheckpoint(name string) (checkpoint, error) {
+	var cp checkpoint
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return cp, err
+	}
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return cp, fmt.Errorf("invalid checkpoint file %s: %v", name, err)
+	}
+	return cp, nil
+}
+
+// writeCheckpoint replaces the checkpoint file by renaming, so a crash never leaves a
+// broken file.
+func writeCheckpoint(name string, cp checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp := name + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// resumeLoad makes the load insert the keys after the checkpoint, and returns false if
+// all the keys are inserted.
+func (c *Client) resumeLoad(w ycsb.CheckpointWorkload, name string) bool {
+	cp, err := readCheckpoint(name)
+	if err != nil {
+		util.Fatalf("read the checkpoint to resume failed: %v", err)
+	}
+	if err = w.Resume(cp.Next, cp.End); err != nil {
+		util.Fatalf("resume the load failed: %v", err)
+	}
+	if cp.Next >= cp.End {
+		fmt.Printf("all the keys before %d are inserted, nothing to resume\n", cp.End)
+		return false
+	}
+
+	fmt.Printf("resume the load from the key %d to %d\n", cp.Next, cp.End)
+	c.p.Set(prop.InsertCount, strconv.FormatInt(cp.End-cp.Next, 10))
+	return true
+}
+
+// saveCheckpoints saves the checkpoint of the load periodically, and the last one when
+// the ctx is done.
+func (c *Client) saveCheckpoints(ctx context.Context, w ycsb.CheckpointWorkload, name string) {
+	save := func() {
+		next, end := w.Checkpoint()
+		if err := writeCheckpoint(name, checkpoint{Next: next, End: end}); err != nil {
+			fmt.Printf("save the checkpoint failed: %v\n", err)
+		}
+	}
+
+	t := time.NewTicker(c.p.GetParsedDuration(prop.CheckpointInterval, prop.CheckpointIntervalDefault))
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			save()
+		case <-ctx.Done():
+			save()
+			return
+		}
+	}
+}
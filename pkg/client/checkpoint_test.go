@@ -0,0 +1,105 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/measurement"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+func TestResumeAndAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	checkpointFile := "checkpoint.file=" + filepath.Join(dir, "load.checkpoint")
+
+	// Load the first 600 records, like a failed load.
+	db := newTestDB(t)
+	runTest(t, db, false, checkpointFile, "insertcount=600", "recordcount=600")
+	cp, err := readCheckpoint(filepath.Join(dir, "load.checkpoint"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Next != 600 {
+		t.Fatalf("want the checkpoint at 600, but got %d", cp.Next)
+	}
+
+	// Resume the load of 1000 records from the checkpoint.
+	if err = writeCheckpoint(filepath.Join(dir, "load.checkpoint"), checkpoint{Next: 600, End: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	counts := runTest(t, db, false, checkpointFile, "load.resume=true")
+	checkNoErrors(t, counts)
+	if counts["INSERT"] != 400 || countRecords(t, db) != 1000 {
+		t.Fatalf("want 400 records resumed, but got %d inserts, %d records", counts["INSERT"], countRecords(t, db))
+	}
+
+	// Append 200 records after the loaded ones.
+	counts = runTest(t, db, false, "load.append=true", "insertcount=200")
+	checkNoErrors(t, counts)
+	if counts["INSERT"] != 200 || countRecords(t, db) != 1200 {
+		t.Fatalf("want 200 records appended, but got %d inserts, %d records", counts["INSERT"], countRecords(t, db))
+	}
+
+	// The run reads the appended records.
+	checkNoErrors(t, runTest(t, db, true))
+}
+
+func TestCheckpointAfterFailedInsert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := properties.MustLoadString(testWorkload)
+	p.Set(prop.DoTransactions, "false")
+	p.Set(prop.InsertOrder, "ordered")
+	p.Set(prop.CheckpointFile, filepath.Join(dir, "load.checkpoint"))
+	p.Set(faultErrorRate, "0.01")
+	measurement.InitMeasure(p)
+	w, err := ycsb.GetWorkloadCreator("core").Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	db := newTestDB(t)
+	NewClient(p, w, DbWrapper{NewFaultyDB(p, db)}).Run(context.Background())
+
+	// The failed keys are never inserted, so the checkpoint stays before the first one.
+	cp, err := readCheckpoint(filepath.Join(dir, "load.checkpoint"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Next >= 1000 {
+		t.Fatalf("want the checkpoint before a failed key, but got %d", cp.Next)
+	}
+	for i := int64(0); i < cp.Next; i++ {
+		if _, err = db.Read(context.Background(), prop.TableNameDefault, fmt.Sprintf("user%d", i), nil); err != nil {
+			t.Fatalf("want the keys before the checkpoint %d inserted, but got %v", cp.Next, err)
+		}
+	}
+}
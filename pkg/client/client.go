@@ -24,6 +24,7 @@ import (
 	"github.com/magiconair/properties"
 	"github.com/pingcap/go-ycsb/pkg/measurement"
 	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
 	"github.com/pingcap/go-ycsb/pkg/ycsb"
 )
 
@@ -64,7 +65,9 @@ func newWorker(p *properties.Properties, threadID int, threadCount int, workload
 		}
 	}
 
-	if totalOpCount < int64(threadCount) {
+	// A run bounded by maxexecutiontime may leave the operation count unlimited.
+	unlimited := totalOpCount == 0 && p.GetInt64(prop.MaxExecutiontime, 0) > 0
+	if !unlimited && totalOpCount < int64(threadCount) {
 		fmt.Printf("totalOpCount(%s/%s/%s): %d should be bigger than threadCount: %d",
 			prop.OperationCount,
 			prop.InsertCount,
@@ -175,7 +178,47 @@ func (c *Client) Run(ctx context.Context) {
 	var wg sync.WaitGroup
 	threadCount := c.p.GetInt(prop.ThreadCount, 1)
 
+	doTransactions := c.p.GetBool(prop.DoTransactions, true)
+	metadataWorkload, useMetadata := c.workload.(ycsb.MetadataWorkload)
+	useMetadata = useMetadata && c.p.GetBool(prop.Metadata, prop.MetadataDefault)
+	loaded := false
+	if useMetadata && doTransactions {
+		var err error
+		if loaded, err = c.checkMetadata(ctx, metadataWorkload); err != nil {
+			util.Fatal(err)
+		}
+	}
+	if !doTransactions && c.p.GetBool(prop.LoadAppend, prop.LoadAppendDefault) {
+		if !useMetadata {
+			util.Fatal("must have the load metadata to append the records")
+		}
+		found, err := c.checkMetadata(ctx, metadataWorkload)
+		if err != nil {
+			util.Fatal(err)
+		} else if !found {
+			util.Fatal("must have the load metadata to append the records")
+		}
+	}
+
+	checkpointWorkload, useCheckpoint := c.workload.(ycsb.CheckpointWorkload)
+	checkpointFile := c.p.GetString(prop.CheckpointFile, "")
+	useCheckpoint = useCheckpoint && !doTransactions && len(checkpointFile) > 0
+	if !doTransactions && c.p.GetBool(prop.LoadResume, prop.LoadResumeDefault) {
+		if !useCheckpoint {
+			util.Fatalf("must set %s to resume the load of the workload supporting checkpoints", prop.CheckpointFile)
+		}
+		if !c.resumeLoad(checkpointWorkload, checkpointFile) {
+			return
+		}
+	}
+
 	wg.Add(threadCount)
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+	if dur := c.p.GetInt64(prop.MaxExecutiontime, 0); dur > 0 {
+		runCtx, runCancel = context.WithTimeout(ctx, time.Duration(dur)*time.Second)
+		defer runCancel()
+	}
 	measureCtx, measureCancel := context.WithCancel(ctx)
 	measureCh := make(chan struct{}, 1)
 	go func() {
@@ -208,12 +251,21 @@ func (c *Client) Run(ctx context.Context) {
 		}
 	}()
 
+	checkpointCtx, checkpointCancel := context.WithCancel(ctx)
+	checkpointCh := make(chan struct{})
+	go func() {
+		defer close(checkpointCh)
+		if useCheckpoint {
+			c.saveCheckpoints(checkpointCtx, checkpointWorkload, checkpointFile)
+		}
+	}()
+
 	for i := 0; i < threadCount; i++ {
 		go func(threadId int) {
 			defer wg.Done()
 
 			w := newWorker(c.p, threadId, threadCount, c.workload, c.db)
-			ctx := c.workload.InitThread(ctx, threadId, threadCount)
+			ctx := c.workload.InitThread(runCtx, threadId, threadCount)
 			ctx = c.db.InitThread(ctx, threadId, threadCount)
 			w.run(ctx)
 			c.db.CleanupThread(ctx)
@@ -222,6 +274,11 @@ func (c *Client) Run(ctx context.Context) {
 	}
 
 	wg.Wait()
+	checkpointCancel()
+	<-checkpointCh
+	if useMetadata && (loaded || !doTransactions) {
+		c.writeMetadata(ctx, metadataWorkload)
+	}
 	if !c.p.GetBool(prop.DoTransactions, true) {
 		// when loading is finished, try to analyze table if possible.
 		if analyzeDB, ok := c.db.(ycsb.AnalyzeDB); ok {
@@ -0,0 +1,232 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties"
+	_ "github.com/pingcap/go-ycsb/db/memory"
+	"github.com/pingcap/go-ycsb/pkg/measurement"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	_ "github.com/pingcap/go-ycsb/pkg/workload"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+const testWorkload = `
+recordcount=1000
+operationcount=2000
+threadcount=4
+keynamespace=none
+fieldcount=4
+fieldlength=16
+fieldlengthdistribution=constant
+dataintegrity=true
+readproportion=0.4
+updateproportion=0.3
+scanproportion=0.1
+insertproportion=0.2
+requestdistribution=zipfian
+`
+
+func newTestDB(t *testing.T) ycsb.DB {
+	db, err := ycsb.GetDBCreator("memory").Create(properties.NewProperties())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// runTest runs the core workload on the db, and returns the operation counts.
+func runTest(t *testing.T, db ycsb.DB, doTransactions bool, s ...string) map[string]int64 {
+	p := properties.MustLoadString(testWorkload + strings.Join(s, "\n"))
+	if doTransactions {
+		p.Set(prop.DoTransactions, "true")
+	} else {
+		p.Set(prop.DoTransactions, "false")
+	}
+	measurement.InitMeasure(p)
+
+	w, err := ycsb.GetWorkloadCreator("core").Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	NewClient(p, w, DbWrapper{db}).Run(context.Background())
+
+	counts := make(map[string]int64)
+	for op, info := range measurement.Info() {
+		counts[op] = info.Get(measurement.COUNT).(int64)
+	}
+	return counts
+}
+
+func countRecords(t *testing.T, db ycsb.DB) int {
+	rows, err := db.Scan(context.Background(), prop.TableNameDefault, "", 1<<30, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(rows)
+}
+
+func checkNoErrors(t *testing.T, counts map[string]int64) {
+	t.Helper()
+	for op, count := range counts {
+		if strings.HasSuffix(op, "_ERROR") {
+			t.Fatalf("want no errors, but got %d %s", count, op)
+		}
+	}
+}
+
+func TestLoadAndRun(t *testing.T) {
+	db := newTestDB(t)
+
+	counts := runTest(t, db, false)
+	checkNoErrors(t, counts)
+	if counts["INSERT"] != 1000 || countRecords(t, db) != 1000 {
+		t.Fatalf("want 1000 records loaded, but got %d inserts, %d records", counts["INSERT"], countRecords(t, db))
+	}
+
+	counts = runTest(t, db, true)
+	checkNoErrors(t, counts)
+	if counts["READ"]+counts["UPDATE"]+counts["SCAN"]+counts["INSERT"] != 2000 {
+		t.Fatalf("want 2000 operations, but got %v", counts)
+	}
+	if n := countRecords(t, db); n != 1000+int(counts["INSERT"]) {
+		t.Fatalf("want %d records, but got %d", 1000+counts["INSERT"], n)
+	}
+
+	// The next run inserts after the keys inserted by the last run.
+	inserts := counts["INSERT"]
+	counts = runTest(t, db, true)
+	checkNoErrors(t, counts)
+	if n := countRecords(t, db); n != 1000+int(inserts+counts["INSERT"]) {
+		t.Fatalf("want %d records, but got %d", 1000+inserts+counts["INSERT"], n)
+	}
+}
+
+func TestBatchLoad(t *testing.T) {
+	db := newTestDB(t)
+
+	counts := runTest(t, db, false, "batch.size=10")
+	checkNoErrors(t, counts)
+	if counts["BATCH_INSERT"] != 100 || countRecords(t, db) != 1000 {
+		t.Fatalf("want 1000 records loaded in batches, but got %v", counts)
+	}
+}
+
+func TestMetadataNamespaces(t *testing.T) {
+	db := newTestDB(t)
+
+	// Every namespace has its own metadata, so the second load doesn't overwrite the first.
+	runTest(t, db, false, "keynamespace=explicit", "keynamespace.value=a")
+	runTest(t, db, false, "keynamespace=explicit", "keynamespace.value=b", "keyformat=uuid4")
+	if n := countRecords(t, db); n != 2000 {
+		t.Fatalf("want 2000 records, but got %d", n)
+	}
+
+	checkNoErrors(t, runTest(t, db, true, "keynamespace=explicit", "keynamespace.value=a"))
+}
+
+func TestMetadataMerge(t *testing.T) {
+	db := newTestDB(t)
+
+	// The clients of the same namespace load the parts of the data, the last one loads the
+	// first part.
+	runTest(t, db, false, "keynamespace=explicit", "keynamespace.value=a", "insertstart=500", "insertcount=500")
+	runTest(t, db, false, "keynamespace=explicit", "keynamespace.value=a", "insertstart=0", "insertcount=500")
+
+	s, err := newMetadataStore(properties.MustLoadString(testWorkload), db, "core/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.read(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["maxinsertkey"] != "999" {
+		t.Fatalf("want the keys up to 999 loaded, but got %s", m["maxinsertkey"])
+	}
+}
+
+// newMetadataClient creates the client of the core workload to check or write the
+// metadata.
+func newMetadataClient(t *testing.T, db ycsb.DB, s ...string) (*Client, ycsb.MetadataWorkload) {
+	p := properties.MustLoadString(testWorkload + strings.Join(s, "\n"))
+	w, err := ycsb.GetWorkloadCreator("core").Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(p, w, DbWrapper{db}), w.(ycsb.MetadataWorkload)
+}
+
+func TestMetadataMismatch(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	c, w := newMetadataClient(t, db)
+	if found, err := c.checkMetadata(ctx, w); found || err != nil {
+		t.Fatalf("want no metadata before the load, but got %v %v", found, err)
+	}
+
+	runTest(t, db, false)
+	if found, err := c.checkMetadata(ctx, w); !found || err != nil {
+		t.Fatalf("want the matched metadata, but got %v %v", found, err)
+	}
+
+	// The run can't find the records loaded with other keys.
+	c, w = newMetadataClient(t, db, "keyformat=uuid4")
+	found, err := c.checkMetadata(ctx, w)
+	if !found || err == nil || !strings.Contains(err.Error(), "keyformat") {
+		t.Fatalf("want a keyformat mismatch, but got %v %v", found, err)
+	}
+}
+
+// failReadDB fails to read the metadata table.
+type failReadDB struct {
+	ycsb.DB
+}
+
+func (db failReadDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	if strings.HasSuffix(table, "_metadata") {
+		return nil, ErrInjectedFault
+	}
+	return db.DB.Read(ctx, table, key, fields)
+}
+
+func TestMetadataReadFailure(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	// The metadata is not written when the read fails, so the metadata of the first load
+	// is kept.
+	runTest(t, db, false, "insertstart=0", "insertcount=500")
+	c, w := newMetadataClient(t, failReadDB{db}, "insertstart=500", "insertcount=500")
+	c.writeMetadata(ctx, w)
+
+	s, err := newMetadataStore(c.p, db, w.MetadataKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := s.read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["maxinsertkey"] != "499" {
+		t.Fatalf("want the keys up to 499 recorded, but got %s", m["maxinsertkey"])
+	}
+}
@@ -15,6 +15,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -27,16 +28,52 @@ type DbWrapper struct {
 	DB ycsb.DB
 }
 
+// errorOp returns the operation name to measure the error.
+func errorOp(op string, err error) string {
+	if errors.Is(err, ycsb.ErrNotSupported) {
+		return fmt.Sprintf("%s_UNSUPPORTED", op)
+	}
+	return fmt.Sprintf("%s_ERROR", op)
+}
+
 func measure(start time.Time, op string, err error) {
 	lan := time.Now().Sub(start)
 	if err != nil {
-		measurement.Measure(fmt.Sprintf("%s_ERROR", op), lan)
+		measurement.Measure(errorOp(op, err), lan)
 		return
 	}
 
 	measurement.Measure(op, lan)
 }
 
+func measureBytes(start time.Time, op string, bytes int64, err error) {
+	lan := time.Now().Sub(start)
+	if err != nil {
+		measurement.Measure(errorOp(op, err), lan)
+		return
+	}
+
+	measurement.MeasureBytes(op, lan, bytes)
+}
+
+// valuesSize returns the payload bytes of a record.
+func valuesSize(values map[string][]byte) int64 {
+	size := int64(0)
+	for _, value := range values {
+		size += int64(len(value))
+	}
+	return size
+}
+
+// rowsSize returns the payload bytes of records.
+func rowsSize(rows []map[string][]byte) int64 {
+	size := int64(0)
+	for _, values := range rows {
+		size += valuesSize(values)
+	}
+	return size
+}
+
 func (db DbWrapper) Close() error {
 	return db.DB.Close()
 }
@@ -49,21 +86,21 @@ func (db DbWrapper) CleanupThread(ctx context.Context) {
 	db.DB.CleanupThread(ctx)
 }
 
-func (db DbWrapper) Read(ctx context.Context, table string, key string, fields []string) (_ map[string][]byte, err error) {
+func (db DbWrapper) Read(ctx context.Context, table string, key string, fields []string) (values map[string][]byte, err error) {
 	start := time.Now()
 	defer func() {
-		measure(start, "READ", err)
+		measureBytes(start, "READ", valuesSize(values), err)
 	}()
 
 	return db.DB.Read(ctx, table, key, fields)
 }
 
-func (db DbWrapper) BatchRead(ctx context.Context, table string, keys []string, fields []string) (_ []map[string][]byte, err error) {
+func (db DbWrapper) BatchRead(ctx context.Context, table string, keys []string, fields []string) (rows []map[string][]byte, err error) {
 	batchDB, ok := db.DB.(ycsb.BatchDB)
 	if ok {
 		start := time.Now()
 		defer func() {
-			measure(start, "BATCH_READ", err)
+			measureBytes(start, "BATCH_READ", rowsSize(rows), err)
 		}()
 		return batchDB.BatchRead(ctx, table, keys, fields)
 	}
@@ -76,10 +113,10 @@ func (db DbWrapper) BatchRead(ctx context.Context, table string, keys []string,
 	return nil, nil
 }
 
-func (db DbWrapper) Scan(ctx context.Context, table string, startKey string, count int, fields []string) (_ []map[string][]byte, err error) {
+func (db DbWrapper) Scan(ctx context.Context, table string, startKey string, count int, fields []string) (rows []map[string][]byte, err error) {
 	start := time.Now()
 	defer func() {
-		measure(start, "SCAN", err)
+		measureBytes(start, "SCAN", rowsSize(rows), err)
 	}()
 
 	return db.DB.Scan(ctx, table, startKey, count, fields)
@@ -88,7 +125,7 @@ func (db DbWrapper) Scan(ctx context.Context, table string, startKey string, cou
 func (db DbWrapper) Update(ctx context.Context, table string, key string, values map[string][]byte) (err error) {
 	start := time.Now()
 	defer func() {
-		measure(start, "UPDATE", err)
+		measureBytes(start, "UPDATE", valuesSize(values), err)
 	}()
 
 	return db.DB.Update(ctx, table, key, values)
@@ -99,7 +136,7 @@ func (db DbWrapper) BatchUpdate(ctx context.Context, table string, keys []string
 	if ok {
 		start := time.Now()
 		defer func() {
-			measure(start, "BATCH_UPDATE", err)
+			measureBytes(start, "BATCH_UPDATE", rowsSize(values), err)
 		}()
 		return batchDB.BatchUpdate(ctx, table, keys, values)
 	}
@@ -115,7 +152,7 @@ func (db DbWrapper) BatchUpdate(ctx context.Context, table string, keys []string
 func (db DbWrapper) Insert(ctx context.Context, table string, key string, values map[string][]byte) (err error) {
 	start := time.Now()
 	defer func() {
-		measure(start, "INSERT", err)
+		measureBytes(start, "INSERT", valuesSize(values), err)
 	}()
 
 	return db.DB.Insert(ctx, table, key, values)
@@ -126,7 +163,7 @@ func (db DbWrapper) BatchInsert(ctx context.Context, table string, keys []string
 	if ok {
 		start := time.Now()
 		defer func() {
-			measure(start, "BATCH_INSERT", err)
+			measureBytes(start, "BATCH_INSERT", rowsSize(values), err)
 		}()
 		return batchDB.BatchInsert(ctx, table, keys, values)
 	}
@@ -172,3 +209,182 @@ func (db DbWrapper) Analyze(ctx context.Context, table string) error {
 	}
 	return nil
 }
+
+func (db DbWrapper) queryDB() (ycsb.QueryDB, error) {
+	queryDB, ok := db.DB.(ycsb.QueryDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the QueryDB interface", db.DB)
+	}
+	return queryDB, nil
+}
+
+func (db DbWrapper) CreateIndex(ctx context.Context, table string, field string) error {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return err
+	}
+	return queryDB.CreateIndex(ctx, table, field)
+}
+
+func (db DbWrapper) Query(ctx context.Context, table string, field string, value []byte, fields []string) (rows []map[string][]byte, err error) {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	defer func() {
+		measureBytes(start, "QUERY", rowsSize(rows), err)
+	}()
+
+	return queryDB.Query(ctx, table, field, value, fields)
+}
+
+func (db DbWrapper) RangeQuery(ctx context.Context, table string, field string, startValue []byte, count int, fields []string) (rows []map[string][]byte, err error) {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	defer func() {
+		measureBytes(start, "RANGE_QUERY", rowsSize(rows), err)
+	}()
+
+	return queryDB.RangeQuery(ctx, table, field, startValue, count, fields)
+}
+
+func (db DbWrapper) Count(ctx context.Context, table string, field string, startValue []byte, endValue []byte) (_ int64, err error) {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	defer func() {
+		measure(start, "COUNT", err)
+	}()
+
+	return queryDB.Count(ctx, table, field, startValue, endValue)
+}
+
+func (db DbWrapper) rangeDB() (ycsb.RangeDB, error) {
+	rangeDB, ok := db.DB.(ycsb.RangeDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the RangeDB interface", db.DB)
+	}
+	return rangeDB, nil
+}
+
+func (db DbWrapper) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) (rows []map[string][]byte, err error) {
+	rangeDB, err := db.rangeDB()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	defer func() {
+		measureBytes(start, "SCAN_RANGE", rowsSize(rows), err)
+	}()
+
+	return rangeDB.ScanRange(ctx, table, startKey, endKey, limit, fields)
+}
+
+func (db DbWrapper) DeleteRange(ctx context.Context, table string, startKey string, endKey string) (err error) {
+	rangeDB, err := db.rangeDB()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	defer func() {
+		measure(start, "DELETE_RANGE", err)
+	}()
+
+	return rangeDB.DeleteRange(ctx, table, startKey, endKey)
+}
+
+func (db DbWrapper) objectDB() (ycsb.ObjectDB, error) {
+	objectDB, ok := db.DB.(ycsb.ObjectDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the ObjectDB interface", db.DB)
+	}
+	return objectDB, nil
+}
+
+func (db DbWrapper) PutObject(ctx context.Context, bucket string, key string, data []byte) (err error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	defer func() {
+		measureBytes(start, "PUT", int64(len(data)), err)
+	}()
+
+	return objectDB.PutObject(ctx, bucket, key, data)
+}
+
+func (db DbWrapper) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) (data []byte, err error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return nil, err
+	}
+	op := "GET"
+	if offset > 0 || length > 0 {
+		op = "GET_RANGE"
+	}
+	start := time.Now()
+	defer func() {
+		measureBytes(start, op, int64(len(data)), err)
+	}()
+
+	return objectDB.GetObject(ctx, bucket, key, offset, length)
+}
+
+func (db DbWrapper) HeadObject(ctx context.Context, bucket string, key string) (_ int64, err error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	defer func() {
+		measure(start, "HEAD", err)
+	}()
+
+	return objectDB.HeadObject(ctx, bucket, key)
+}
+
+func (db DbWrapper) ListObjects(ctx context.Context, bucket string, prefix string, count int) (_ []string, err error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	defer func() {
+		measure(start, "LIST", err)
+	}()
+
+	return objectDB.ListObjects(ctx, bucket, prefix, count)
+}
+
+func (db DbWrapper) DeleteObject(ctx context.Context, bucket string, key string) (err error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	defer func() {
+		measure(start, "DELETE", err)
+	}()
+
+	return objectDB.DeleteObject(ctx, bucket, key)
+}
+
+func (db DbWrapper) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) (err error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	defer func() {
+		measureBytes(start, "MULTIPART_PUT", int64(len(data)), err)
+	}()
+
+	return objectDB.MultipartUpload(ctx, bucket, key, data, partSize)
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/measurement"
+)
+
+func TestMeasureBytes(t *testing.T) {
+	ctx := context.Background()
+	measurement.InitMeasure(properties.NewProperties())
+	db := DbWrapper{newTestDB(t)}
+
+	// The records have 3 + 5 bytes of values.
+	values := map[string][]byte{"field0": []byte("abc"), "field1": []byte("defgh")}
+	for _, key := range []string{"k1", "k2"} {
+		if err := db.Insert(ctx, "t", key, values); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Read(ctx, "t", "k1", []string{"field1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Scan(ctx, "t", "k1", 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Update(ctx, "t", "k1", map[string][]byte{"field0": []byte("x")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutObject(ctx, "b", "o1", make([]byte, 1000)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetObject(ctx, "b", "o1", 100, 300); err != nil {
+		t.Fatal(err)
+	}
+	// The failed operations don't count the bytes.
+	if err := db.Update(ctx, "t", "k3", values); err == nil {
+		t.Fatal("want an error for updating a missing key")
+	}
+
+	info := measurement.Info()
+	for op, want := range map[string][2]int64{
+		"INSERT":    {2, 16},
+		"READ":      {1, 5},
+		"SCAN":      {1, 16},
+		"UPDATE":    {1, 1},
+		"PUT":       {1, 1000},
+		"GET_RANGE": {1, 300},
+	} {
+		i, ok := info[op]
+		if !ok {
+			t.Fatalf("no measurement of %s", op)
+		}
+		if count, bytes := i.Get(measurement.COUNT).(int64), i.Get(measurement.BYTES).(int64); count != want[0] || bytes != want[1] {
+			t.Fatalf("want %d %s of %d bytes, but got %d of %d bytes", want[0], op, want[1], count, bytes)
+		}
+		if avg := i.Get(measurement.AVGSIZE).(int64); avg != want[1]/want[0] {
+			t.Fatalf("want the average size %d of %s, but got %d", want[1]/want[0], op, avg)
+		}
+		if mbps := i.Get(measurement.THROUGHPUT).(float64); mbps <= 0 {
+			t.Fatalf("want the positive throughput of %s, but got %f", op, mbps)
+		}
+	}
+	if i := info["UPDATE_ERROR"]; i == nil || i.Get(measurement.BYTES).(int64) != 0 {
+		t.Fatalf("want an UPDATE_ERROR without bytes, but got %v", i)
+	}
+}
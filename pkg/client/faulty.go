@@ -0,0 +1,498 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// The fault properties.
+const (
+	faultPrefix = "fault."
+	// The error rate of all the operations, and of every operation type like fault.read.errorrate.
+	faultErrorRate = "fault.errorrate"
+	// The latency spike added to the operations at the spike rate.
+	faultSpikeLatency = "fault.spike.latency"
+	faultSpikeRate    = "fault.spike.rate"
+	// All the operations stall for the duration at the end of every interval.
+	faultStallInterval = "fault.stall.interval"
+	faultStallDuration = "fault.stall.duration"
+	// The rate of the scans returning part of the records.
+	faultPartialScanRate = "fault.scan.partialrate"
+	// The operations run with the deadline at the deadline rate.
+	faultDeadline     = "fault.deadline"
+	faultDeadlineRate = "fault.deadline.rate"
+)
+
+// The operation types of the faults, every operation of the DB is one of them.
+const (
+	faultRead   = "read"
+	faultScan   = "scan"
+	faultUpdate = "update"
+	faultInsert = "insert"
+	faultDelete = "delete"
+)
+
+var faultOps = []string{faultRead, faultScan, faultUpdate, faultInsert, faultDelete}
+
+// ErrInjectedFault is returned by the operations failed by the fault error rates.
+var ErrInjectedFault = errors.New("injected fault")
+
+// FaultyDB injects faults to the operations of the DB, configured by the fault properties.
+type FaultyDB struct {
+	DB ycsb.DB
+
+	errorRates      map[string]float64
+	spikeLatency    time.Duration
+	spikeRate       float64
+	stallInterval   time.Duration
+	stallDuration   time.Duration
+	partialScanRate float64
+	deadline        time.Duration
+	deadlineRate    float64
+	start           time.Time
+}
+
+// NewFaultyDB wraps the DB to inject the faults, or returns the DB itself if no fault
+// property is set.
+func NewFaultyDB(p *properties.Properties, db ycsb.DB) ycsb.DB {
+	if p.FilterPrefix(faultPrefix).Len() == 0 {
+		return db
+	}
+
+	f := FaultyDB{
+		DB:              db,
+		errorRates:      make(map[string]float64, len(faultOps)),
+		spikeLatency:    p.GetParsedDuration(faultSpikeLatency, 0),
+		spikeRate:       p.GetFloat64(faultSpikeRate, 0),
+		stallInterval:   p.GetParsedDuration(faultStallInterval, 0),
+		stallDuration:   p.GetParsedDuration(faultStallDuration, 0),
+		partialScanRate: p.GetFloat64(faultPartialScanRate, 0),
+		deadline:        p.GetParsedDuration(faultDeadline, time.Millisecond),
+		deadlineRate:    p.GetFloat64(faultDeadlineRate, 0),
+		start:           time.Now(),
+	}
+	errorRate := p.GetFloat64(faultErrorRate, 0)
+	for _, op := range faultOps {
+		f.errorRates[op] = p.GetFloat64(faultPrefix+op+".errorrate", errorRate)
+	}
+
+	if f.stallDuration > f.stallInterval {
+		util.Fatalf("%s must not be longer than %s", faultStallDuration, faultStallInterval)
+	}
+	return f
+}
+
+// inject sleeps for the stalls and the latency spikes, then returns the context of the
+// operation with the deadline, and the injected error if the operation fails.
+func (db FaultyDB) inject(ctx context.Context, op string) (context.Context, context.CancelFunc, error) {
+	var sleep time.Duration
+	if db.stallInterval > 0 {
+		// Stall until the end of the interval.
+		phase := time.Since(db.start) % db.stallInterval
+		if phase >= db.stallInterval-db.stallDuration {
+			sleep += db.stallInterval - phase
+		}
+	}
+	if db.spikeRate > 0 && rand.Float64() < db.spikeRate {
+		sleep += db.spikeLatency
+	}
+	if sleep > 0 {
+		select {
+		case <-time.After(sleep):
+		case <-ctx.Done():
+			return ctx, func() {}, ctx.Err()
+		}
+	}
+
+	if rate := db.errorRates[op]; rate > 0 && rand.Float64() < rate {
+		return ctx, func() {}, ErrInjectedFault
+	}
+	if db.deadlineRate > 0 && rand.Float64() < db.deadlineRate {
+		ctx, cancel := context.WithTimeout(ctx, db.deadline)
+		return ctx, cancel, nil
+	}
+	return ctx, func() {}, nil
+}
+
+// expired returns the deadline error if the driver ignores the expired context.
+func expired(ctx context.Context, err error) error {
+	if err == nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// partial returns part of the scanned rows at the partial scan rate.
+func (db FaultyDB) partial(rows []map[string][]byte) []map[string][]byte {
+	if len(rows) > 0 && db.partialScanRate > 0 && rand.Float64() < db.partialScanRate {
+		return rows[:rand.Intn(len(rows))]
+	}
+	return rows
+}
+
+func (db FaultyDB) Close() error {
+	return db.DB.Close()
+}
+
+func (db FaultyDB) InitThread(ctx context.Context, threadID int, threadCount int) context.Context {
+	return db.DB.InitThread(ctx, threadID, threadCount)
+}
+
+func (db FaultyDB) CleanupThread(ctx context.Context) {
+	db.DB.CleanupThread(ctx)
+}
+
+func (db FaultyDB) Read(ctx context.Context, table string, key string, fields []string) (map[string][]byte, error) {
+	ctx, cancel, err := db.inject(ctx, faultRead)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := db.DB.Read(ctx, table, key, fields)
+	return values, expired(ctx, err)
+}
+
+func (db FaultyDB) Scan(ctx context.Context, table string, startKey string, count int, fields []string) ([]map[string][]byte, error) {
+	ctx, cancel, err := db.inject(ctx, faultScan)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.DB.Scan(ctx, table, startKey, count, fields)
+	return db.partial(rows), expired(ctx, err)
+}
+
+func (db FaultyDB) Update(ctx context.Context, table string, key string, values map[string][]byte) error {
+	ctx, cancel, err := db.inject(ctx, faultUpdate)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, db.DB.Update(ctx, table, key, values))
+}
+
+func (db FaultyDB) Insert(ctx context.Context, table string, key string, values map[string][]byte) error {
+	ctx, cancel, err := db.inject(ctx, faultInsert)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, db.DB.Insert(ctx, table, key, values))
+}
+
+func (db FaultyDB) Delete(ctx context.Context, table string, key string) error {
+	ctx, cancel, err := db.inject(ctx, faultDelete)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, db.DB.Delete(ctx, table, key))
+}
+
+func (db FaultyDB) BatchInsert(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	batchDB, ok := db.DB.(ycsb.BatchDB)
+	if !ok {
+		for i := range keys {
+			if err := db.Insert(ctx, table, keys[i], values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	ctx, cancel, err := db.inject(ctx, faultInsert)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, batchDB.BatchInsert(ctx, table, keys, values))
+}
+
+func (db FaultyDB) BatchRead(ctx context.Context, table string, keys []string, fields []string) ([]map[string][]byte, error) {
+	batchDB, ok := db.DB.(ycsb.BatchDB)
+	if !ok {
+		rows := make([]map[string][]byte, 0, len(keys))
+		for _, key := range keys {
+			values, err := db.Read(ctx, table, key, fields)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, values)
+		}
+		return rows, nil
+	}
+	ctx, cancel, err := db.inject(ctx, faultRead)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := batchDB.BatchRead(ctx, table, keys, fields)
+	return rows, expired(ctx, err)
+}
+
+func (db FaultyDB) BatchUpdate(ctx context.Context, table string, keys []string, values []map[string][]byte) error {
+	batchDB, ok := db.DB.(ycsb.BatchDB)
+	if !ok {
+		for i := range keys {
+			if err := db.Update(ctx, table, keys[i], values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	ctx, cancel, err := db.inject(ctx, faultUpdate)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, batchDB.BatchUpdate(ctx, table, keys, values))
+}
+
+func (db FaultyDB) BatchDelete(ctx context.Context, table string, keys []string) error {
+	batchDB, ok := db.DB.(ycsb.BatchDB)
+	if !ok {
+		for _, key := range keys {
+			if err := db.Delete(ctx, table, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	ctx, cancel, err := db.inject(ctx, faultDelete)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, batchDB.BatchDelete(ctx, table, keys))
+}
+
+func (db FaultyDB) Analyze(ctx context.Context, table string) error {
+	if analyzeDB, ok := db.DB.(ycsb.AnalyzeDB); ok {
+		return analyzeDB.Analyze(ctx, table)
+	}
+	return nil
+}
+
+func (db FaultyDB) queryDB() (ycsb.QueryDB, error) {
+	queryDB, ok := db.DB.(ycsb.QueryDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the QueryDB interface", db.DB)
+	}
+	return queryDB, nil
+}
+
+func (db FaultyDB) CreateIndex(ctx context.Context, table string, field string) error {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return err
+	}
+	return queryDB.CreateIndex(ctx, table, field)
+}
+
+func (db FaultyDB) Query(ctx context.Context, table string, field string, value []byte, fields []string) ([]map[string][]byte, error) {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := db.inject(ctx, faultRead)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queryDB.Query(ctx, table, field, value, fields)
+	return rows, expired(ctx, err)
+}
+
+func (db FaultyDB) RangeQuery(ctx context.Context, table string, field string, start []byte, count int, fields []string) ([]map[string][]byte, error) {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := db.inject(ctx, faultScan)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := queryDB.RangeQuery(ctx, table, field, start, count, fields)
+	return db.partial(rows), expired(ctx, err)
+}
+
+func (db FaultyDB) Count(ctx context.Context, table string, field string, start []byte, end []byte) (int64, error) {
+	queryDB, err := db.queryDB()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel, err := db.inject(ctx, faultScan)
+	defer cancel()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := queryDB.Count(ctx, table, field, start, end)
+	return n, expired(ctx, err)
+}
+
+func (db FaultyDB) rangeDB() (ycsb.RangeDB, error) {
+	rangeDB, ok := db.DB.(ycsb.RangeDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the RangeDB interface", db.DB)
+	}
+	return rangeDB, nil
+}
+
+func (db FaultyDB) ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error) {
+	rangeDB, err := db.rangeDB()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := db.inject(ctx, faultScan)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := rangeDB.ScanRange(ctx, table, startKey, endKey, limit, fields)
+	return db.partial(rows), expired(ctx, err)
+}
+
+func (db FaultyDB) DeleteRange(ctx context.Context, table string, startKey string, endKey string) error {
+	rangeDB, err := db.rangeDB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := db.inject(ctx, faultDelete)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, rangeDB.DeleteRange(ctx, table, startKey, endKey))
+}
+
+func (db FaultyDB) objectDB() (ycsb.ObjectDB, error) {
+	objectDB, ok := db.DB.(ycsb.ObjectDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the ObjectDB interface", db.DB)
+	}
+	return objectDB, nil
+}
+
+func (db FaultyDB) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := db.inject(ctx, faultInsert)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, objectDB.PutObject(ctx, bucket, key, data))
+}
+
+func (db FaultyDB) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := db.inject(ctx, faultRead)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := objectDB.GetObject(ctx, bucket, key, offset, length)
+	return data, expired(ctx, err)
+}
+
+func (db FaultyDB) HeadObject(ctx context.Context, bucket string, key string) (int64, error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel, err := db.inject(ctx, faultRead)
+	defer cancel()
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := objectDB.HeadObject(ctx, bucket, key)
+	return size, expired(ctx, err)
+}
+
+func (db FaultyDB) ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error) {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := db.inject(ctx, faultScan)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := objectDB.ListObjects(ctx, bucket, prefix, count)
+	if len(keys) > 0 && db.partialScanRate > 0 && rand.Float64() < db.partialScanRate {
+		keys = keys[:rand.Intn(len(keys))]
+	}
+	return keys, expired(ctx, err)
+}
+
+func (db FaultyDB) DeleteObject(ctx context.Context, bucket string, key string) error {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := db.inject(ctx, faultDelete)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, objectDB.DeleteObject(ctx, bucket, key))
+}
+
+func (db FaultyDB) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error {
+	objectDB, err := db.objectDB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := db.inject(ctx, faultInsert)
+	defer cancel()
+	if err != nil {
+		return err
+	}
+
+	return expired(ctx, objectDB.MultipartUpload(ctx, bucket, key, data, partSize))
+}
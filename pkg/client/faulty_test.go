@@ -0,0 +1,119 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+func newTestFaultyDB(t *testing.T, s string) ycsb.DB {
+	db := NewFaultyDB(properties.MustLoadString(s), newTestDB(t))
+	if _, ok := db.(FaultyDB); !ok {
+		t.Fatalf("want the faulty db, but got %T", db)
+	}
+	return db
+}
+
+func TestNoFaults(t *testing.T) {
+	db := newTestDB(t)
+	if NewFaultyDB(properties.MustLoadString("recordcount=10"), db) != db {
+		t.Fatal("want the db itself without the fault properties")
+	}
+}
+
+func TestFaultErrorRates(t *testing.T) {
+	ctx := context.Background()
+	db := newTestFaultyDB(t, "fault.errorrate=0.5\nfault.read.errorrate=1")
+
+	values := map[string][]byte{"field0": []byte("a")}
+	failed := 0
+	for i := 0; i < 200; i++ {
+		if err := db.Insert(ctx, "t", fmt.Sprintf("k%d", i), values); err == ErrInjectedFault {
+			failed++
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if failed < 50 || failed > 150 {
+		t.Fatalf("want about half of the inserts failed, but got %d", failed)
+	}
+
+	if _, err := db.Read(ctx, "t", "k0", nil); err != ErrInjectedFault {
+		t.Fatalf("want all the reads failed, but got %v", err)
+	}
+	if _, err := db.(ycsb.BatchDB).BatchRead(ctx, "t", []string{"k0"}, nil); err != ErrInjectedFault {
+		t.Fatalf("want all the batch reads failed, but got %v", err)
+	}
+}
+
+func TestFaultPartialScan(t *testing.T) {
+	ctx := context.Background()
+	db := newTestFaultyDB(t, "fault.scan.partialrate=1")
+
+	for i := 0; i < 10; i++ {
+		if err := db.Insert(ctx, "t", fmt.Sprintf("k%d", i), map[string][]byte{"field0": []byte("a")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows, err := db.Scan(ctx, "t", "k0", 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) >= 10 {
+		t.Fatalf("want part of the records, but got %d", len(rows))
+	}
+}
+
+func TestFaultDeadline(t *testing.T) {
+	p := properties.MustLoadString("fault.deadline=1ms\nfault.deadline.rate=1")
+	db, err := ycsb.GetDBCreator("memory").Create(properties.MustLoadString("memory.latency=20ms"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = NewFaultyDB(p, db).Insert(context.Background(), "t", "k", map[string][]byte{"field0": []byte("a")})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("want the deadline exceeded, but got %v", err)
+	}
+}
+
+func TestFaultStall(t *testing.T) {
+	db := newTestFaultyDB(t, "fault.stall.interval=100ms\nfault.stall.duration=100ms")
+
+	start := time.Now()
+	if err := db.Insert(context.Background(), "t", "k", map[string][]byte{"field0": []byte("a")}); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Fatalf("want the insert stalled, but it takes %s", d)
+	}
+}
+
+func TestInsertionRetry(t *testing.T) {
+	db := NewFaultyDB(properties.MustLoadString("fault.insert.errorrate=0.3"), newTestDB(t))
+
+	counts := runTest(t, db, false, "core_workload_insertion_retry_limit=20", "core_workload_insertion_retry_interval=0")
+	if counts["INSERT_ERROR"] == 0 {
+		t.Fatalf("want the injected errors counted, but got %v", counts)
+	}
+	if counts["INSERT"] != 1000 || countRecords(t, db) != 1000 {
+		t.Fatalf("want all the records inserted by retrying, but got %v", counts)
+	}
+}
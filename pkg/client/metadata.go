@@ -0,0 +1,151 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// metadataStore reads and writes the metadata record of the loaded data. The record is
+// kept in its own table, out of the records scanned by the workload. The metadata is
+// encoded in JSON and stored in the first bytes or string field of the schema. The SQL,
+// Cassandra and Spanner drivers create the table with util.MetadataTable.
+type metadataStore struct {
+	db    ycsb.DB
+	table string
+	key   string
+	field string
+}
+
+func newMetadataStore(p *properties.Properties, db ycsb.DB, key string) (*metadataStore, error) {
+	// Access the database directly, so the metadata is not measured and has no faults.
+	if w, ok := db.(DbWrapper); ok {
+		db = w.DB
+	}
+	if f, ok := db.(FaultyDB); ok {
+		db = f.DB
+	}
+
+	f := util.LoadSchema(p).MetadataField()
+	if f == nil {
+		return nil, fmt.Errorf("no bytes or string field to store the metadata")
+	}
+	return &metadataStore{
+		db:    db,
+		table: util.MetadataTable(p),
+		key:   key,
+		field: f.Name,
+	}, nil
+}
+
+// read returns the metadata, or nil if there is no metadata. A missing record is read
+// as no values or ycsb.ErrNotFound, and the other errors fail the read.
+func (s *metadataStore) read(ctx context.Context) (map[string]string, error) {
+	ctx = s.db.InitThread(ctx, 0, 1)
+	defer s.db.CleanupThread(ctx)
+
+	values, err := s.db.Read(ctx, s.table, s.key, []string{s.field})
+	if errors.Is(err, ycsb.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	data := values[s.field]
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var m map[string]string
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid metadata %q: %v", data, err)
+	}
+	return m, nil
+}
+
+// write inserts the record if create is set, otherwise updates it.
+func (s *metadataStore) write(ctx context.Context, m map[string]string, create bool) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	ctx = s.db.InitThread(ctx, 0, 1)
+	defer s.db.CleanupThread(ctx)
+
+	values := map[string][]byte{s.field: data}
+	if create {
+		return s.db.Insert(ctx, s.table, s.key, values)
+	}
+	return s.db.Update(ctx, s.table, s.key, values)
+}
+
+// checkMetadata checks the metadata recorded by the load before running, and returns
+// whether the metadata is found. It fails if the loaded data doesn't match.
+func (c *Client) checkMetadata(ctx context.Context, w ycsb.MetadataWorkload) (bool, error) {
+	s, err := newMetadataStore(c.p, c.db, w.MetadataKey())
+	if err != nil {
+		fmt.Printf("skip checking the load metadata: %v\n", err)
+		return false, nil
+	}
+
+	m, err := s.read(ctx)
+	if err != nil {
+		fmt.Printf("skip checking the load metadata, read failed: %v\n", err)
+		return false, nil
+	} else if m == nil {
+		fmt.Println("no load metadata is found, skip checking")
+		return false, nil
+	}
+
+	if err = w.CheckMetadata(m); err != nil {
+		return true, fmt.Errorf("the data loaded doesn't match: %v", err)
+	}
+	return true, nil
+}
+
+// writeMetadata records the metadata when the load finishes, or updates it with the
+// keys inserted when the run finishes. The metadata recorded by the other clients in the
+// meantime is merged.
+func (c *Client) writeMetadata(ctx context.Context, w ycsb.MetadataWorkload) {
+	s, err := newMetadataStore(c.p, c.db, w.MetadataKey())
+	if err != nil {
+		fmt.Printf("write the load metadata failed: %v\n", err)
+		return
+	}
+
+	// The write is skipped if the read fails, or it may overwrite the metadata of the
+	// other clients.
+	m := w.Metadata()
+	old, err := s.read(ctx)
+	if err != nil {
+		fmt.Printf("write the load metadata failed, read failed: %v\n", err)
+		return
+	}
+	if old != nil {
+		if m, err = w.MergeMetadata(old, m); err != nil {
+			fmt.Printf("write the load metadata failed: %v\n", err)
+			return
+		}
+	}
+	if err = s.write(ctx, m, old == nil); err != nil {
+		fmt.Printf("write the load metadata failed: %v\n", err)
+	}
+}
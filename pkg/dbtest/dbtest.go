@@ -0,0 +1,178 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dbtest checks that the database drivers implement the DB interfaces the same way,
+// so the tests of every driver only create the database and call the checks.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// Row returns a record of the fields field0 and field1, where field0 is v.
+func Row(v string) map[string][]byte {
+	return map[string][]byte{"field0": []byte(v), "field1": []byte(v + v)}
+}
+
+func initThread(db ycsb.DB) (context.Context, func()) {
+	ctx := db.InitThread(context.Background(), 0, 1)
+	return ctx, func() {
+		db.CleanupThread(ctx)
+	}
+}
+
+// TestCRUD checks the operations of a single record in the table "t". An update of a
+// missing key must fail.
+func TestCRUD(t *testing.T, db ycsb.DB) {
+	t.Helper()
+	ctx, cleanup := initThread(db)
+	defer cleanup()
+
+	if err := db.Insert(ctx, "t", "k1", Row("a")); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := db.Read(ctx, "t", "k1", []string{"field1"}); err != nil || !reflect.DeepEqual(m, map[string][]byte{"field1": []byte("aa")}) {
+		t.Fatalf("read field1 got %q, %v", m, err)
+	}
+
+	values := map[string][]byte{"field0": []byte("b")}
+	if err := db.Update(ctx, "t", "k1", values); err != nil {
+		t.Fatal(err)
+	}
+	// The stored values must not change with the buffers of the workload.
+	values["field0"][0] = 'x'
+	if m, err := db.Read(ctx, "t", "k1", nil); err != nil || !reflect.DeepEqual(m, map[string][]byte{"field0": []byte("b"), "field1": []byte("aa")}) {
+		t.Fatalf("read all got %q, %v", m, err)
+	}
+
+	if err := db.Update(ctx, "t", "k2", Row("b")); err == nil {
+		t.Fatal("want an error for updating a missing key")
+	}
+	if err := db.Delete(ctx, "t", "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Read(ctx, "t", "k1", nil); err == nil {
+		t.Fatal("want an error for reading a deleted key")
+	}
+}
+
+// TestScan checks the scans of the ordered keys in the table "t", which must not read the
+// records of the other tables. The range operations are checked if the db is a RangeDB.
+func TestScan(t *testing.T, db ycsb.DB) {
+	t.Helper()
+	ctx, cleanup := initThread(db)
+	defer cleanup()
+
+	var keys []string
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("user%03d", i)
+		keys = append(keys, key)
+		if err := db.Insert(ctx, "t", key, Row(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Insert(ctx, "u", keys[0], Row("u")); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(rows []map[string][]byte, want []string) {
+		t.Helper()
+		if len(rows) != len(want) {
+			t.Fatalf("want %d rows, but got %d", len(want), len(rows))
+		}
+		for i, row := range rows {
+			if string(row["field0"]) != want[i] {
+				t.Fatalf("want row %d %s, but got %s", i, want[i], row["field0"])
+			}
+		}
+	}
+
+	rows, err := db.Scan(ctx, "t", keys[10], 20, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(rows, keys[10:30])
+
+	rows, err = db.Scan(ctx, "t", keys[45], 20, []string{"field0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(rows, keys[45:])
+
+	rangeDB, ok := db.(ycsb.RangeDB)
+	if !ok {
+		if err = db.Delete(ctx, "t", keys[11]); err != nil {
+			t.Fatal(err)
+		}
+		rows, err = db.Scan(ctx, "t", keys[10], 3, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(rows, []string{keys[10], keys[12], keys[13]})
+		return
+	}
+
+	rows, err = rangeDB.ScanRange(ctx, "t", keys[10], keys[20], 100, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(rows, keys[10:20])
+
+	if err = rangeDB.DeleteRange(ctx, "t", keys[10], keys[20]); err != nil {
+		t.Fatal(err)
+	}
+	rows, err = rangeDB.ScanRange(ctx, "t", keys[5], keys[25], 100, []string{"field0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(rows, append(keys[5:10:10], keys[20:25]...))
+}
+
+// TestBatch checks the batch operations in the table "t". A batch read of the missing
+// keys must fail.
+func TestBatch(t *testing.T, bdb ycsb.DB) {
+	t.Helper()
+	db, ok := bdb.(ycsb.BatchDB)
+	if !ok {
+		t.Fatalf("the %T doesn't implement the BatchDB interface", bdb)
+	}
+	ctx, cleanup := initThread(bdb)
+	defer cleanup()
+
+	keys := []string{"a", "b", "c"}
+	if err := db.BatchInsert(ctx, "t", keys, []map[string][]byte{Row("a"), Row("b"), Row("c")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.BatchUpdate(ctx, "t", keys[:2], []map[string][]byte{{"field0": []byte("x")}, {"field0": []byte("y")}}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.BatchRead(ctx, "t", keys, []string{"field0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []map[string][]byte{{"field0": []byte("x")}, {"field0": []byte("y")}, {"field0": []byte("c")}}; !reflect.DeepEqual(rows, want) {
+		t.Fatalf("want %q, but got %q", want, rows)
+	}
+
+	if err = db.BatchDelete(ctx, "t", keys[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.BatchRead(ctx, "t", keys, nil); err == nil {
+		t.Fatal("want an error for reading the deleted keys")
+	}
+}
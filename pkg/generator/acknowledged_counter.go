@@ -52,7 +52,9 @@ type AcknowledgedCounter struct {
 
 	lock util.SpinLock
 
-	window []bool
+	// window marks the acknowledged slots with 1. The slots are set by the threads
+	// without the lock, so they are accessed atomically.
+	window []uint32
 	limit  int64
 }
 
@@ -61,7 +63,7 @@ func NewAcknowledgedCounter(start int64) *AcknowledgedCounter {
 	return &AcknowledgedCounter{
 		c:      Counter{counter: start},
 		lock:   util.SpinLock{},
-		window: make([]bool, WindowSize),
+		window: make([]uint32, WindowSize),
 		limit:  start - 1,
 	}
 }
@@ -79,12 +81,10 @@ func (a *AcknowledgedCounter) Last() int64 {
 // Acknowledge makes a generated counter vaailable via Last.
 func (a *AcknowledgedCounter) Acknowledge(value int64) {
 	currentSlot := value & WindowMask
-	if a.window[currentSlot] {
+	if !atomic.CompareAndSwapUint32(&a.window[currentSlot], 0, 1) {
 		panic("Too many unacknowledged insertion keys.")
 	}
 
-	a.window[currentSlot] = true
-
 	if !a.lock.TryLock() {
 		return
 	}
@@ -99,11 +99,11 @@ func (a *AcknowledgedCounter) Acknowledge(value int64) {
 	index := limit + 1
 	for ; index != beforeFirstSlot; index++ {
 		slot := index & WindowMask
-		if !a.window[slot] {
+		if atomic.LoadUint32(&a.window[slot]) == 0 {
 			break
 		}
 
-		a.window[slot] = false
+		atomic.StoreUint32(&a.window[slot], 0)
 	}
 
 	atomic.StoreInt64(&a.limit, index-1)
@@ -0,0 +1,164 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/go-ycsb/pkg/util"
+)
+
+// Empirical generates keys following the access frequencies of popularity ranks, which
+// are usually sampled from a real access log. It uses the alias method to draw a rank in O(1).
+type Empirical struct {
+	Number
+	min       int64
+	itemCount int64
+	scrambled bool
+	prob      []float64
+	alias     []int64
+}
+
+// NewEmpirical creates an Empirical generator.
+// min: the lower bound of the keys.
+// max: the upper bound of the keys.
+// freqs: the access frequency of every rank, the rank 0 is the hottest one.
+// scrambled: whether to spread the ranks over the keys, otherwise the rank i is the key min + i.
+func NewEmpirical(min int64, max int64, freqs []int64, scrambled bool) *Empirical {
+	e := &Empirical{
+		min:       min,
+		itemCount: max - min + 1,
+		scrambled: scrambled,
+	}
+	e.prob, e.alias = newAliasTable(freqs)
+	return e
+}
+
+// newAliasTable builds the alias table with the Vose's method.
+func newAliasTable(freqs []int64) ([]float64, []int64) {
+	n := len(freqs)
+	if n == 0 {
+		util.Fatalf("the empirical distribution needs at least one rank")
+	}
+
+	var sum int64
+	for _, f := range freqs {
+		sum += f
+	}
+	if sum <= 0 {
+		util.Fatalf("the empirical distribution needs a positive access frequency")
+	}
+
+	prob := make([]float64, n)
+	alias := make([]int64, n)
+	scaled := make([]float64, n)
+	small := make([]int64, 0, n)
+	large := make([]int64, 0, n)
+	for i, f := range freqs {
+		scaled[i] = float64(f) * float64(n) / float64(sum)
+		if scaled[i] < 1.0 {
+			small = append(small, int64(i))
+		} else {
+			large = append(large, int64(i))
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		l := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		prob[l] = scaled[l]
+		alias[l] = g
+		scaled[g] = scaled[g] + scaled[l] - 1.0
+		if scaled[g] < 1.0 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+
+	// The remaining ones are 1 except for the floating-point errors.
+	for _, i := range large {
+		prob[i] = 1.0
+	}
+	for _, i := range small {
+		prob[i] = 1.0
+	}
+
+	return prob, alias
+}
+
+// LoadFrequencyFile loads the access frequencies of the ranks. Every line of the file is
+// a rank and its frequency, separated by a tab, and the missing ranks have no access.
+// It fails if no rank has a positive frequency.
+func LoadFrequencyFile(name string) ([]int64, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("load frequency file %s failed %v", name, err)
+	}
+
+	var freqs []int64
+	var sum int64
+	for _, s := range strings.Split(string(data), "\n") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 || strings.HasPrefix(s, "#") {
+			continue
+		}
+
+		line := strings.Split(s, "\t")
+		if len(line) != 2 {
+			return nil, fmt.Errorf("invalid line %q in frequency file %s", s, name)
+		}
+		rank, err := strconv.ParseInt(line[0], 10, 64)
+		if err != nil || rank < 0 {
+			return nil, fmt.Errorf("invalid rank %q in frequency file %s", line[0], name)
+		}
+		freq, err := strconv.ParseInt(line[1], 10, 64)
+		if err != nil || freq < 0 {
+			return nil, fmt.Errorf("invalid frequency %q in frequency file %s", line[1], name)
+		}
+
+		for int64(len(freqs)) <= rank {
+			freqs = append(freqs, 0)
+		}
+		sum += freq - freqs[rank]
+		freqs[rank] = freq
+	}
+
+	if sum <= 0 {
+		return nil, fmt.Errorf("no positive frequency in frequency file %s", name)
+	}
+	return freqs, nil
+}
+
+// Next implements the Generator Next interface.
+func (e *Empirical) Next(r *rand.Rand) int64 {
+	rank := r.Int63n(int64(len(e.prob)))
+	if r.Float64() >= e.prob[rank] {
+		rank = e.alias[rank]
+	}
+
+	if e.scrambled {
+		rank = util.Hash64(rank)
+	}
+	v := e.min + rank%e.itemCount
+	e.SetLastValue(v)
+	return v
+}
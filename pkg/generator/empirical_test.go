@@ -0,0 +1,87 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEmpirical(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	freqs := []int64{50, 25, 0, 15, 10}
+	e := NewEmpirical(100, 199, freqs, false)
+
+	const n = 200000
+	counts := make(map[int64]int)
+	for i := 0; i < n; i++ {
+		counts[e.Next(r)]++
+	}
+
+	if counts[102] != 0 {
+		t.Fatalf("the rank without access is generated %d times", counts[102])
+	}
+	for rank, f := range freqs {
+		got := float64(counts[100+int64(rank)]) / n
+		want := float64(f) / 100
+		if math.Abs(got-want) > 0.01 {
+			t.Fatalf("rank %d: want frequency %.3f, but got %.3f", rank, want, got)
+		}
+	}
+}
+
+func TestEmpiricalScrambled(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	e := NewEmpirical(0, 9, []int64{1, 1, 1, 1}, true)
+	for i := 0; i < 1000; i++ {
+		if v := e.Next(r); v < 0 || v > 9 {
+			t.Fatalf("want in [0, 9], but got %d", v)
+		}
+	}
+}
+
+func TestLoadFrequencyFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "keyfreq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString("# rank\tfrequency\n0\t10\n3\t2\n1\t5\n")
+	f.Close()
+
+	freqs, err := LoadFrequencyFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{10, 5, 0, 2}; !reflect.DeepEqual(freqs, want) {
+		t.Fatalf("want %v, but got %v", want, freqs)
+	}
+
+	for _, s := range []string{"0 10\n", "a\t10\n", "-1\t10\n", "0\tb\n", "0\t-1\n", "0\t0\n1\t0\n", "# empty\n"} {
+		if err = ioutil.WriteFile(f.Name(), []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = LoadFrequencyFile(f.Name()); err == nil {
+			t.Fatalf("want an error for the frequency file %q", s)
+		}
+	}
+	if _, err = LoadFrequencyFile(f.Name() + ".missing"); err == nil {
+		t.Fatal("want an error for the missing frequency file")
+	}
+}
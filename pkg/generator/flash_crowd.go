@@ -0,0 +1,91 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// FlashCrowd generates integers with the basis generator, except at the end of every
+// interval, when a crowd suddenly accesses a small set of items at a random place.
+type FlashCrowd struct {
+	Number
+	period
+	basis            ycsb.Generator
+	lowerBound       int64
+	itemCount        int64
+	crowdItems       int64
+	duration         time.Duration
+	crowdOpnFraction float64
+}
+
+// NewFlashCrowd creates a FlashCrowd generator.
+// basis: the generator used out of the flash crowds.
+// lowerBound: the lower bound of the distribution.
+// upperBound: the upper bound of the distribution.
+// interval: the time between the starts of two flash crowds.
+// duration: how long a flash crowd lasts.
+// crowdFraction: percentage of data item accessed by a flash crowd.
+// crowdOpnFraction: percentage of operations accessing the crowd items during a flash crowd.
+func NewFlashCrowd(basis ycsb.Generator, lowerBound int64, upperBound int64, interval time.Duration,
+	duration time.Duration, crowdFraction float64, crowdOpnFraction float64) *FlashCrowd {
+	if crowdOpnFraction < 0.0 || crowdOpnFraction > 1.0 {
+		crowdOpnFraction = 0.0
+	}
+
+	if lowerBound > upperBound {
+		lowerBound, upperBound = upperBound, lowerBound
+	}
+
+	itemCount := upperBound - lowerBound + 1
+	crowdItems := int64(float64(itemCount) * crowdFraction)
+	if crowdItems < 1 {
+		crowdItems = 1
+	} else if crowdItems > itemCount {
+		crowdItems = itemCount
+	}
+
+	return &FlashCrowd{
+		period:           newPeriod(interval),
+		basis:            basis,
+		lowerBound:       lowerBound,
+		itemCount:        itemCount,
+		crowdItems:       crowdItems,
+		duration:         duration,
+		crowdOpnFraction: crowdOpnFraction,
+	}
+}
+
+// inCrowd returns whether a flash crowd is going on.
+func (f *FlashCrowd) inCrowd() bool {
+	return f.offset() >= f.interval-f.duration
+}
+
+// Next implements the Generator Next interface.
+func (f *FlashCrowd) Next(r *rand.Rand) int64 {
+	var value int64
+	if f.inCrowd() && r.Float64() < f.crowdOpnFraction {
+		// Every flash crowd starts at a different place.
+		start := util.Hash64(f.index()) % f.itemCount
+		value = f.lowerBound + (start+r.Int63n(f.crowdItems))%f.itemCount
+	} else {
+		value = f.basis.Next(r)
+	}
+	f.SetLastValue(value)
+	return value
+}
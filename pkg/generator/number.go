@@ -30,17 +30,20 @@
 
 package generator
 
-// Number is a common generator.
+import "sync/atomic"
+
+// Number is a common generator. The generators are shared by the threads, so the last
+// value is accessed atomically.
 type Number struct {
 	LastValue int64
 }
 
 // SetLastValue sets the last value generated.
 func (n *Number) SetLastValue(value int64) {
-	n.LastValue = value
+	atomic.StoreInt64(&n.LastValue, value)
 }
 
 // Last implements the Generator Last interface.
 func (n *Number) Last() int64 {
-	return n.LastValue
+	return atomic.LoadInt64(&n.LastValue)
 }
@@ -0,0 +1,45 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import "time"
+
+// period splits the time since the generator is created into intervals of the same
+// length, so a generator can change its distribution in every interval.
+type period struct {
+	start    time.Time
+	interval time.Duration
+	now      func() time.Time
+}
+
+func newPeriod(interval time.Duration) period {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return period{
+		start:    time.Now(),
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// index returns the number of the intervals passed.
+func (p *period) index() int64 {
+	return int64(p.now().Sub(p.start) / p.interval)
+}
+
+// offset returns the time passed in the current interval.
+func (p *period) offset() time.Duration {
+	return p.now().Sub(p.start) % p.interval
+}
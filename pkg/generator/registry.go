@@ -0,0 +1,200 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// Args are the arguments of a generator which depend on where the generator is used.
+type Args struct {
+	// Min is the smallest value to generate.
+	Min int64
+	// Max is the largest value to generate, and the value of the "constant" generator.
+	Max int64
+	// Scrambled spreads the popular values of the skewed generators over the range,
+	// which is used to choose keys.
+	Scrambled bool
+	// Basis is the counter of the inserted keys, used by the "latest" generator, and
+	// the scrambled "zipfian" generator to grow its items with the inserts.
+	Basis ycsb.Generator
+	// HistogramFile is the file loaded by the "histogram" generator.
+	HistogramFile string
+	// Weights are the weights of the values chosen by the "discrete" generator, which is
+	// used to mix the operations.
+	Weights map[int64]float64
+}
+
+// Creator creates a generator.
+type Creator interface {
+	Create(p *properties.Properties, args Args) (ycsb.Generator, error)
+}
+
+// CreatorFunc is an adapter to use a function as the Creator.
+type CreatorFunc func(p *properties.Properties, args Args) (ycsb.Generator, error)
+
+// Create implements the Creator Create interface.
+func (f CreatorFunc) Create(p *properties.Properties, args Args) (ycsb.Generator, error) {
+	return f(p, args)
+}
+
+var creators = map[string]Creator{}
+
+// RegisterCreator registers a creator for the generator. The name is case-insensitive.
+func RegisterCreator(name string, creator Creator) {
+	name = strings.ToLower(name)
+	_, ok := creators[name]
+	if ok {
+		panic(fmt.Sprintf("duplicate register generator %s", name))
+	}
+
+	creators[name] = creator
+}
+
+// GetCreator gets the Creator for the generator.
+func GetCreator(name string) Creator {
+	return creators[strings.ToLower(name)]
+}
+
+// New creates the generator registered with the name.
+func New(name string, p *properties.Properties, args Args) (ycsb.Generator, error) {
+	creator := GetCreator(name)
+	if creator == nil {
+		return nil, fmt.Errorf("unknown distribution %s", name)
+	}
+	return creator.Create(p, args)
+}
+
+// getZipfianConstant returns zipfian.constant, which must be in (0, 1) for the zipfian
+// generator.
+func getZipfianConstant(p *properties.Properties) (float64, error) {
+	zipfianConstant := p.GetFloat64(prop.ZipfianConstant, prop.ZipfianConstantDefault)
+	if zipfianConstant <= 0 || zipfianConstant >= 1 {
+		return 0, fmt.Errorf("%s must be in (0, 1), but got %v", prop.ZipfianConstant, zipfianConstant)
+	}
+	return zipfianConstant, nil
+}
+
+func init() {
+	RegisterCreator("constant", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		return NewConstant(args.Max), nil
+	}))
+	RegisterCreator("uniform", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		return NewUniform(args.Min, args.Max), nil
+	}))
+	RegisterCreator("sequential", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		return NewSequential(args.Min, args.Max), nil
+	}))
+	RegisterCreator("zipfian", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		zipfianConstant, err := getZipfianConstant(p)
+		if err != nil {
+			return nil, err
+		}
+		if args.Scrambled && args.Basis != nil {
+			return NewScrambledZipfianWithBasis(args.Min, args.Basis, zipfianConstant), nil
+		} else if args.Scrambled {
+			return NewScrambledZipfian(args.Min, args.Max, zipfianConstant), nil
+		}
+		return NewZipfianWithRange(args.Min, args.Max, zipfianConstant), nil
+	}))
+	RegisterCreator("discrete", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		// Add the values in order, so a seeded workload chooses the same values.
+		values := make([]int64, 0, len(args.Weights))
+		for value, weight := range args.Weights {
+			if weight < 0 {
+				return nil, fmt.Errorf("the weight %v of %d is negative", weight, value)
+			}
+			if weight > 0 {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("the discrete distribution needs a positive weight")
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		d := NewDiscrete()
+		for _, value := range values {
+			d.Add(args.Weights[value], value)
+		}
+		return d, nil
+	}))
+	RegisterCreator("latest", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		if args.Basis == nil {
+			return nil, fmt.Errorf("the latest distribution is only allowed to choose keys")
+		}
+		zipfianConstant, err := getZipfianConstant(p)
+		if err != nil {
+			return nil, err
+		}
+		return NewSkewedLatestWithConstant(args.Basis, zipfianConstant), nil
+	}))
+	RegisterCreator("hotspot", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		hotsetFraction := p.GetFloat64(prop.HotspotDataFraction, prop.HotspotDataFractionDefault)
+		hotopnFraction := p.GetFloat64(prop.HotspotOpnFraction, prop.HotspotOpnFractionDefault)
+		return NewHotspot(args.Min, args.Max, hotsetFraction, hotopnFraction), nil
+	}))
+	RegisterCreator("exponential", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		recordCount := p.GetInt64(prop.RecordCount, prop.RecordCountDefault)
+		if recordCount == 0 {
+			recordCount = int64(math.MaxInt32)
+		}
+		percentile := p.GetFloat64(prop.ExponentialPercentile, prop.ExponentialPercentileDefault)
+		frac := p.GetFloat64(prop.ExponentialFrac, prop.ExponentialFracDefault)
+		return NewExponential(percentile, float64(recordCount)*frac), nil
+	}))
+	RegisterCreator("histogram", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		if len(args.HistogramFile) == 0 {
+			return nil, fmt.Errorf("the histogram distribution needs a histogram file")
+		}
+		return NewHistogramFromFile(args.HistogramFile), nil
+	}))
+	RegisterCreator("empirical", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		freqs, err := LoadFrequencyFile(p.GetString(prop.EmpiricalFile, prop.EmpiricalFileDefault))
+		if err != nil {
+			return nil, err
+		}
+		return NewEmpirical(args.Min, args.Max, freqs, args.Scrambled), nil
+	}))
+	RegisterCreator("rotatinghotspot", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		hotsetFraction := p.GetFloat64(prop.HotspotDataFraction, prop.HotspotDataFractionDefault)
+		hotopnFraction := p.GetFloat64(prop.HotspotOpnFraction, prop.HotspotOpnFractionDefault)
+		interval := p.GetParsedDuration(prop.HotspotInterval, prop.HotspotIntervalDefault)
+		shiftFraction := p.GetFloat64(prop.HotspotShiftFraction, hotsetFraction)
+		return NewRotatingHotspot(args.Min, args.Max, hotsetFraction, hotopnFraction, interval, shiftFraction), nil
+	}))
+	RegisterCreator("reshuffledzipfian", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		interval := p.GetParsedDuration(prop.ZipfianShuffleInterval, prop.ZipfianShuffleIntervalDefault)
+		zipfianConstant, err := getZipfianConstant(p)
+		if err != nil {
+			return nil, err
+		}
+		return NewReshuffledZipfian(args.Min, args.Max, zipfianConstant, interval), nil
+	}))
+	RegisterCreator("flashcrowd", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		interval := p.GetParsedDuration(prop.FlashCrowdInterval, prop.FlashCrowdIntervalDefault)
+		duration := p.GetParsedDuration(prop.FlashCrowdDuration, prop.FlashCrowdDurationDefault)
+		crowdFraction := p.GetFloat64(prop.FlashCrowdDataFraction, prop.FlashCrowdDataFractionDefault)
+		crowdOpnFraction := p.GetFloat64(prop.FlashCrowdOpnFraction, prop.FlashCrowdOpnFractionDefault)
+		basis := NewUniform(args.Min, args.Max)
+		return NewFlashCrowd(basis, args.Min, args.Max, interval, duration, crowdFraction, crowdOpnFraction), nil
+	}))
+}
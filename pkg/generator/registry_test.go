@@ -0,0 +1,93 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+func TestRegistry(t *testing.T) {
+	p := properties.NewProperties()
+	r := rand.New(rand.NewSource(1))
+
+	RegisterCreator("TestSeven", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		return NewConstant(7), nil
+	}))
+	g, err := New("testseven", p, Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := g.Next(r); v != 7 {
+		t.Fatalf("want 7, but got %d", v)
+	}
+
+	g, err = New("Uniform", p, Args{Min: 10, Max: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if v := g.Next(r); v < 10 || v > 20 {
+			t.Fatalf("want in [10, 20], but got %d", v)
+		}
+	}
+
+	g, err = New("discrete", p, Args{Weights: map[int64]float64{1: 0, 2: 1, 3: 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if v := g.Next(r); v != 2 {
+			t.Fatalf("want 2, but got %d", v)
+		}
+	}
+	for _, weights := range []map[int64]float64{nil, {1: 0, 2: 0}, {1: 1, 2: -1}} {
+		if _, err = New("discrete", p, Args{Weights: weights}); err == nil {
+			t.Fatalf("want an error for the discrete distribution of the weights %v", weights)
+		}
+	}
+
+	if _, err = New("unknown", p, Args{}); err == nil {
+		t.Fatal("want an error for the unknown distribution")
+	}
+	if _, err = New("latest", p, Args{Min: 1, Max: 10}); err == nil {
+		t.Fatal("want an error for the latest distribution without basis")
+	}
+
+	for _, c := range []string{"0", "1", "1.5", "-0.5"} {
+		p.Set(prop.ZipfianConstant, c)
+		for _, name := range []string{"zipfian", "latest", "reshuffledzipfian"} {
+			if _, err = New(name, p, Args{Min: 0, Max: 10, Basis: NewCounter(10)}); err == nil {
+				t.Fatalf("want an error for the %s distribution with %s=%s", name, prop.ZipfianConstant, c)
+			}
+		}
+	}
+	p.Set(prop.ZipfianConstant, "0.5")
+	if _, err = New("latest", p, Args{Basis: NewCounter(10)}); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("want a panic for the duplicate register")
+		}
+	}()
+	RegisterCreator("uniform", CreatorFunc(func(p *properties.Properties, args Args) (ycsb.Generator, error) {
+		return nil, nil
+	}))
+}
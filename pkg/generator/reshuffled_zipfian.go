@@ -0,0 +1,47 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/pingcap/go-ycsb/pkg/util"
+)
+
+// ReshuffledZipfian produces a zipfian distribution like ScrambledZipfian, but the
+// popularity ranking of the items is reshuffled after every interval.
+type ReshuffledZipfian struct {
+	Number
+	period
+	scrambled *ScrambledZipfian
+}
+
+// NewReshuffledZipfian creates a ReshuffledZipfian generator.
+func NewReshuffledZipfian(min int64, max int64, zipfianConstant float64, interval time.Duration) *ReshuffledZipfian {
+	return &ReshuffledZipfian{
+		period:    newPeriod(interval),
+		scrambled: NewScrambledZipfian(min, max, zipfianConstant),
+	}
+}
+
+// Next implements the Generator Next interface.
+func (z *ReshuffledZipfian) Next(r *rand.Rand) int64 {
+	n := z.scrambled.gen.Next(r)
+
+	// The zipfian items are less than 2^34, so every interval hashes them with different bits.
+	n = z.scrambled.min + util.Hash64(n^(z.index()<<40))%z.scrambled.itemCount
+	z.SetLastValue(n)
+	return n
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RotatingHotspot generates integers resembling a hotspot distribution like Hotspot,
+// but the hot set moves forward after every interval, and wraps around at the upper bound.
+type RotatingHotspot struct {
+	Number
+	period
+	lowerBound     int64
+	itemCount      int64
+	hotInterval    int64
+	coldInterval   int64
+	shift          int64
+	hotOpnFraction float64
+}
+
+// NewRotatingHotspot creates a RotatingHotspot generator.
+// lowerBound: the lower bound of the distribution.
+// upperBound: the upper bound of the distribution.
+// hotsetFraction: percentage of data item.
+// hotOpnFraction: percentage of operations accessing the hot set.
+// interval: how long the hot set stays at the same place.
+// shiftFraction: percentage of data item the hot set moves after every interval.
+func NewRotatingHotspot(lowerBound int64, upperBound int64, hotsetFraction float64, hotOpnFraction float64,
+	interval time.Duration, shiftFraction float64) *RotatingHotspot {
+	if hotsetFraction < 0.0 || hotsetFraction > 1.0 {
+		hotsetFraction = 0.0
+	}
+
+	if hotOpnFraction < 0.0 || hotOpnFraction > 1.0 {
+		hotOpnFraction = 0.0
+	}
+
+	if shiftFraction < 0.0 || shiftFraction > 1.0 {
+		shiftFraction = hotsetFraction
+	}
+
+	if lowerBound > upperBound {
+		lowerBound, upperBound = upperBound, lowerBound
+	}
+
+	itemCount := upperBound - lowerBound + 1
+	hotInterval := int64(float64(itemCount) * hotsetFraction)
+	return &RotatingHotspot{
+		period:         newPeriod(interval),
+		lowerBound:     lowerBound,
+		itemCount:      itemCount,
+		hotInterval:    hotInterval,
+		coldInterval:   itemCount - hotInterval,
+		shift:          int64(float64(itemCount) * shiftFraction),
+		hotOpnFraction: hotOpnFraction,
+	}
+}
+
+// Next implements the Generator Next interface.
+func (h *RotatingHotspot) Next(r *rand.Rand) int64 {
+	value := (h.index() * h.shift) % h.itemCount
+	if h.hotInterval > 0 && (h.coldInterval == 0 || r.Float64() < h.hotOpnFraction) {
+		value += r.Int63n(h.hotInterval)
+	} else {
+		value += h.hotInterval + r.Int63n(h.coldInterval)
+	}
+	value = h.lowerBound + value%h.itemCount
+	h.SetLastValue(value)
+	return value
+}
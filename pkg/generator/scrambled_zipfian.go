@@ -34,6 +34,7 @@ import (
 	"math/rand"
 
 	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
 )
 
 // ScrambledZipfian produces a sequence of items, such that some items are more popular than
@@ -44,6 +45,8 @@ type ScrambledZipfian struct {
 	min       int64
 	max       int64
 	itemCount int64
+	// basis is the counter of the inserted items if the items grow.
+	basis ycsb.Generator
 }
 
 // NewScrambledZipfian creates a ScrambledZipfian generator.
@@ -66,11 +69,49 @@ func NewScrambledZipfian(min int64, max int64, zipfianConstant float64) *Scrambl
 	return s
 }
 
+// NewScrambledZipfianWithBasis creates a ScrambledZipfian generator whose items grow with
+// the basis, which is the counter of the inserted items. The items from min to the last
+// value of the basis at the beginning are scrambled, and the items inserted later are the
+// least popular ones, in the insertion order.
+func NewScrambledZipfianWithBasis(min int64, basis ycsb.Generator, zipfianConstant float64) *ScrambledZipfian {
+	max := basis.Last()
+	if max < min {
+		max = min
+	}
+
+	return &ScrambledZipfian{
+		gen:       NewZipfianWithRange(0, max-min, zipfianConstant),
+		min:       min,
+		max:       max,
+		itemCount: max - min + 1,
+		basis:     basis,
+	}
+}
+
 // Next implements the Generator Next interface.
 func (s *ScrambledZipfian) Next(r *rand.Rand) int64 {
+	if s.basis != nil {
+		return s.nextWithBasis(r)
+	}
+
 	n := s.gen.Next(r)
 
 	n = s.min + util.Hash64(n)%s.itemCount
 	s.SetLastValue(n)
 	return n
 }
+
+func (s *ScrambledZipfian) nextWithBasis(r *rand.Rand) int64 {
+	items := s.basis.Last() - s.min + 1
+	if items < s.itemCount {
+		items = s.itemCount
+	}
+
+	n := s.gen.next(r, items)
+	if n < s.itemCount {
+		n = util.Hash64(n) % s.itemCount
+	}
+	n += s.min
+	s.SetLastValue(n)
+	return n
+}
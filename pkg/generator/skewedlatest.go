@@ -48,7 +48,12 @@ type SkewedLatest struct {
 // NewSkewedLatest creates the SkewedLatest generator.
 // basis is Counter or AcknowledgedCounter
 func NewSkewedLatest(basis ycsb.Generator) *SkewedLatest {
-	zipfian := NewZipfianWithItems(basis.Last(), ZipfianConstant)
+	return NewSkewedLatestWithConstant(basis, ZipfianConstant)
+}
+
+// NewSkewedLatestWithConstant creates the SkewedLatest generator with the zipfian constant.
+func NewSkewedLatestWithConstant(basis ycsb.Generator, zipfianConstant float64) *SkewedLatest {
+	zipfian := NewZipfianWithItems(basis.Last(), zipfianConstant)
 	s := &SkewedLatest{
 		basis:   basis,
 		zipfian: zipfian,
@@ -0,0 +1,150 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) setPeriod(p *period, d time.Duration) {
+	c.t = p.start.Add(d)
+	p.now = c.now
+}
+
+func TestRotatingHotspot(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewRotatingHotspot(1000, 1999, 0.1, 1.0, time.Minute, 0.1)
+	clock := new(fakeClock)
+
+	for _, c := range []struct {
+		elapsed time.Duration
+		min     int64
+		max     int64
+	}{
+		{0, 1000, 1099},
+		{59 * time.Second, 1000, 1099},
+		{time.Minute, 1100, 1199},
+		{5*time.Minute + time.Second, 1500, 1599},
+		// wrap around at the upper bound
+		{10 * time.Minute, 1000, 1099},
+	} {
+		clock.setPeriod(&h.period, c.elapsed)
+		for i := 0; i < 1000; i++ {
+			if v := h.Next(r); v < c.min || v > c.max {
+				t.Fatalf("after %s, want in [%d, %d], but got %d", c.elapsed, c.min, c.max, v)
+			}
+		}
+	}
+}
+
+func TestRotatingHotspotColdSet(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewRotatingHotspot(0, 999, 0.1, 0.0, time.Minute, 0.95)
+	clock := new(fakeClock)
+
+	// The hot set is [950, 1049], which wraps to [950, 999] and [0, 49].
+	clock.setPeriod(&h.period, time.Minute)
+	for i := 0; i < 10000; i++ {
+		if v := h.Next(r); v < 50 || v >= 950 {
+			t.Fatalf("want a cold item, but got %d", v)
+		}
+	}
+}
+
+func hottest(g interface{ Next(*rand.Rand) int64 }, r *rand.Rand, n int) int64 {
+	counts := make(map[int64]int)
+	for i := 0; i < n; i++ {
+		counts[g.Next(r)]++
+	}
+	var value int64
+	max := 0
+	for v, c := range counts {
+		if c > max {
+			value, max = v, c
+		}
+	}
+	return value
+}
+
+func TestReshuffledZipfian(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	z := NewReshuffledZipfian(0, 999999, ZipfianConstant, time.Minute)
+	clock := new(fakeClock)
+
+	clock.setPeriod(&z.period, 0)
+	first := hottest(z, r, 100000)
+	clock.setPeriod(&z.period, 30*time.Second)
+	if v := hottest(z, r, 100000); v != first {
+		t.Fatalf("the hottest item changed from %d to %d in the same interval", first, v)
+	}
+
+	clock.setPeriod(&z.period, time.Minute)
+	if v := hottest(z, r, 100000); v == first {
+		t.Fatalf("the hottest item %d is not reshuffled", v)
+	}
+
+	for i := 0; i < 10000; i++ {
+		if v := z.Next(r); v < 0 || v > 999999 {
+			t.Fatalf("want in [0, 999999], but got %d", v)
+		}
+	}
+}
+
+func TestFlashCrowd(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	f := NewFlashCrowd(NewConstant(-1), 0, 9999, time.Minute, 10*time.Second, 0.01, 1.0)
+	clock := new(fakeClock)
+
+	clock.setPeriod(&f.period, 49*time.Second)
+	for i := 0; i < 1000; i++ {
+		if v := f.Next(r); v != -1 {
+			t.Fatalf("want the basis value before the flash crowd, but got %d", v)
+		}
+	}
+
+	crowd := func() map[int64]struct{} {
+		items := make(map[int64]struct{})
+		for i := 0; i < 10000; i++ {
+			v := f.Next(r)
+			if v < 0 || v > 9999 {
+				t.Fatalf("want in [0, 9999], but got %d", v)
+			}
+			items[v] = struct{}{}
+		}
+		if len(items) > 100 {
+			t.Fatalf("want at most 100 crowd items, but got %d", len(items))
+		}
+		return items
+	}
+
+	clock.setPeriod(&f.period, 50*time.Second)
+	first := crowd()
+	clock.setPeriod(&f.period, 119*time.Second)
+	second := crowd()
+	for v := range second {
+		if _, ok := first[v]; ok {
+			t.Fatalf("the flash crowds access the same item %d", v)
+		}
+	}
+}
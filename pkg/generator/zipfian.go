@@ -34,6 +34,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/go-ycsb/pkg/util"
@@ -54,18 +55,19 @@ const (
 // popular, and so on (or min is the most popular, min+1 the next most popular, etc.) If you don't want this clustering,
 // and instead want the popular items scattered throughout the item space, then use ScrambledZipfianGenerator instead.
 //
-// Be aware: initializing this generator may take a long time if there are lots of items to choose from (e.g. over a
-// minute for 100 million objects). This is because certain mathematical values need to be computed to properly
-// generate a zipfian skew, and one of those values (zeta) is a sum sequence from 1 to n, where n is the itemcount.
-// Note that if you increase the number of items in the set, we can compute a new zeta incrementally, so it should be
-// fast unless you have added millions of items. However, if you decrease the number of items, we recompute zeta from
-// scratch, so this can take a long time.
+// Certain mathematical values need to be computed to properly generate a zipfian skew, and one of those values (zeta)
+// is a sum sequence from 1 to n, where n is the itemcount. Only the first terms are summed exactly, and the rest are
+// approximated, so initializing this generator is fast even for billions of items. If you increase the number of items
+// in the set, we compute a new zeta incrementally. However, if you decrease the number of items, we recompute zeta
+// from scratch.
 //
 // The algorithm used here is from "Quickly Generating Billion-Record Synthetic Databases", Jim Gray et al, SIGMOD 1994.
 type Zipfian struct {
 	Number
 
-	lock util.SpinLock
+	// lock serializes the updates of the state, which is read without the lock.
+	lock  util.SpinLock
+	state atomic.Value
 
 	items int64
 	base  int64
@@ -73,16 +75,20 @@ type Zipfian struct {
 	zipfianConstant float64
 
 	alpha      float64
-	zetan      float64
 	theta      float64
-	eta        float64
 	zeta2Theta float64
 
-	countForZeta int64
-
 	allowItemCountDecrease bool
 }
 
+// zipfianState is the immutable zeta of the items. The threads draw with different item
+// counts, so the state is replaced as a whole to keep zetan and eta consistent with it.
+type zipfianState struct {
+	items int64
+	zetan float64
+	eta   float64
+}
+
 // NewZipfianWithItems creates the Zipfian generator.
 func NewZipfianWithItems(items int64, zipfianConstant float64) *Zipfian {
 	return NewZipfianWithRange(0, items-1, zipfianConstant)
@@ -105,51 +111,100 @@ func NewZipfian(min int64, max int64, zipfianConstant float64, zetan float64) *Z
 	theta := z.zipfianConstant
 	z.theta = theta
 
-	z.zeta2Theta = z.zeta(0, 2, theta, 0)
+	z.zeta2Theta = zetaStatic(0, 2, theta, 0)
 
 	z.alpha = 1.0 / (1.0 - theta)
-	z.zetan = zetan
-	z.countForZeta = items
-	z.eta = (1 - math.Pow(2.0/float64(items), 1-theta)) / (1 - z.zeta2Theta/z.zetan)
+	z.state.Store(z.newState(items, zetan))
 
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	z.Next(r)
 	return z
 }
 
-func (z *Zipfian) zeta(st int64, n int64, thetaVal float64, initialSum float64) float64 {
-	z.countForZeta = n
-	return zetaStatic(st, n, thetaVal, initialSum)
+func (z *Zipfian) newState(items int64, zetan float64) *zipfianState {
+	return &zipfianState{
+		items: items,
+		zetan: zetan,
+		eta:   (1 - math.Pow(2.0/float64(items), 1-z.theta)) / (1 - z.zeta2Theta/zetan),
+	}
+}
+
+// updateState computes the state of itemCount items, and returns the latest state. The
+// items only grow unless allowItemCountDecrease is set.
+func (z *Zipfian) updateState(itemCount int64) *zipfianState {
+	z.lock.Lock()
+	defer z.lock.Unlock()
+
+	s := z.state.Load().(*zipfianState)
+	if itemCount > s.items {
+		//we have added more items. can compute zetan incrementally, which is cheaper
+		s = z.newState(itemCount, zetaStatic(s.items, itemCount, z.theta, s.zetan))
+		z.state.Store(s)
+	} else if itemCount < s.items && z.allowItemCountDecrease {
+		//note : for large itemsets, this is very slow. so don't do it!
+		fmt.Printf("recomputing Zipfian distribution, should be avoided,item count %v, count for zeta %v\n", itemCount, s.items)
+		s = z.newState(itemCount, zetaStatic(0, itemCount, z.theta, 0))
+		z.state.Store(s)
+	}
+	return s
 }
 
+// zetaExactItems is the number of the first items whose zeta terms are summed exactly.
+// The terms after them are approximated with the Euler-Maclaurin formula, whose error is
+// far below the float64 precision there, so zeta is fast even for billions of items.
+const zetaExactItems = int64(1 << 16)
+
+// zetaStatic returns initialSum + sum(1/i^theta) for i in (st, n].
 func zetaStatic(st int64, n int64, theta float64, initialSum float64) float64 {
 	sum := initialSum
 
-	for i := st; i < n; i++ {
+	exactEnd := n
+	if exactEnd-st > zetaExactItems {
+		exactEnd = st + zetaExactItems
+		if exactEnd < zetaExactItems {
+			exactEnd = zetaExactItems
+		}
+	}
+
+	for i := st; i < exactEnd; i++ {
 		sum += 1 / math.Pow(float64(i+1), theta)
 	}
 
+	if exactEnd < n {
+		sum += zetaTail(exactEnd+1, n, theta)
+	}
+
 	return sum
 }
 
+// zetaTail approximates sum(1/i^theta) for i in [a, b] with the Euler-Maclaurin formula.
+func zetaTail(a int64, b int64, theta float64) float64 {
+	fa, fb := float64(a), float64(b)
+	f := func(x float64) float64 { return math.Pow(x, -theta) }
+	// the first and third derivatives of f
+	f1 := func(x float64) float64 { return -theta * math.Pow(x, -theta-1) }
+	f3 := func(x float64) float64 { return -theta * (theta + 1) * (theta + 2) * math.Pow(x, -theta-3) }
+
+	var integral float64
+	if theta == 1 {
+		integral = math.Log(fb / fa)
+	} else {
+		integral = (math.Pow(fb, 1-theta) - math.Pow(fa, 1-theta)) / (1 - theta)
+	}
+
+	return integral + (f(fa)+f(fb))/2 + (f1(fb)-f1(fa))/12 - (f3(fb)-f3(fa))/720
+}
+
+// next draws from the items of the state. A thread which sees fewer items than another
+// one draws from the items of the latest state, so the items never go backward.
 func (z *Zipfian) next(r *rand.Rand, itemCount int64) int64 {
-	if itemCount != z.countForZeta {
-		z.lock.Lock()
-		if itemCount > z.countForZeta {
-			//we have added more items. can compute zetan incrementally, which is cheaper
-			z.zetan = z.zeta(z.countForZeta, itemCount, z.theta, z.zetan)
-			z.eta = (1 - math.Pow(2.0/float64(z.items), 1-z.theta)) / (1 - z.zeta2Theta/z.zetan)
-		} else if itemCount < z.countForZeta && z.allowItemCountDecrease {
-			//note : for large itemsets, this is very slow. so don't do it!
-			fmt.Printf("recomputing Zipfian distribution, should be avoided,item count %v, count for zeta %v\n", itemCount, z.countForZeta)
-			z.zetan = z.zeta(0, itemCount, z.theta, 0)
-			z.eta = (1 - math.Pow(2.0/float64(z.items), 1-z.theta)) / (1 - z.zeta2Theta/z.zetan)
-		}
-		z.lock.Unlock()
+	s := z.state.Load().(*zipfianState)
+	if itemCount > s.items || (itemCount < s.items && z.allowItemCountDecrease) {
+		s = z.updateState(itemCount)
 	}
 
 	u := r.Float64()
-	uz := u * z.zetan
+	uz := u * s.zetan
 
 	if uz < 1.0 {
 		return z.base
@@ -159,7 +214,7 @@ func (z *Zipfian) next(r *rand.Rand, itemCount int64) int64 {
 		return z.base + 1
 	}
 
-	ret := z.base + int64(float64(itemCount)*math.Pow(z.eta*u-z.eta+1, z.alpha))
+	ret := z.base + int64(float64(s.items)*math.Pow(s.eta*u-s.eta+1, z.alpha))
 	z.SetLastValue(ret)
 	return ret
 }
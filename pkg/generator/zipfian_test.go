@@ -0,0 +1,143 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func exactZeta(st int64, n int64, theta float64) float64 {
+	sum := float64(0)
+	for i := st; i < n; i++ {
+		sum += 1 / math.Pow(float64(i+1), theta)
+	}
+	return sum
+}
+
+func TestZetaApproximation(t *testing.T) {
+	for _, theta := range []float64{0.5, 0.99, 1.0, 1.2} {
+		for _, c := range []struct {
+			st int64
+			n  int64
+		}{
+			{0, 100},
+			{0, 3000000},
+			{1000, 3000000},
+			{2000000, 3000000},
+		} {
+			want := exactZeta(c.st, c.n, theta)
+			got := zetaStatic(c.st, c.n, theta, 0)
+			if math.Abs(got-want)/want > 1e-12 {
+				t.Fatalf("theta %v, zeta(%d, %d): want %v, but got %v", theta, c.st, c.n, want, got)
+			}
+		}
+	}
+
+	// The constant used by ScrambledZipfian.
+	if got := zetaStatic(0, 10000000000, 0.99, 0); math.Abs(got-26.46902820178302) > 1e-9 {
+		t.Fatalf("want zeta 26.46902820178302, but got %v", got)
+	}
+}
+
+func TestZipfianHugeItems(t *testing.T) {
+	start := time.Now()
+	z := NewZipfianWithItems(100000000000, 0.8)
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("creating the zipfian takes %s", d)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		if v := z.Next(r); v < 0 || v >= 100000000000 {
+			t.Fatalf("want in [0, 100000000000), but got %d", v)
+		}
+	}
+}
+
+func TestScrambledZipfianWithBasis(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	basis := NewAcknowledgedCounter(1000)
+	s := NewScrambledZipfianWithBasis(0, basis, ZipfianConstant)
+
+	hot := hottest(s, r, 100000)
+	for i := 0; i < 1000; i++ {
+		if v := s.Next(r); v < 0 || v >= 1000 {
+			t.Fatalf("want in [0, 1000), but got %d", v)
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		basis.Acknowledge(basis.Next(r))
+	}
+	if basis.Last() != 1999 {
+		t.Fatalf("want last 1999, but got %d", basis.Last())
+	}
+
+	var inserted int
+	for i := 0; i < 100000; i++ {
+		v := s.Next(r)
+		if v < 0 || v >= 2000 {
+			t.Fatalf("want in [0, 2000), but got %d", v)
+		}
+		if v >= 1000 {
+			inserted++
+		}
+	}
+	if inserted == 0 {
+		t.Fatal("the inserted items are never generated")
+	}
+	if v := hottest(s, r, 100000); v != hot {
+		t.Fatalf("the hottest item changes from %d to %d after inserts", hot, v)
+	}
+}
+
+func TestZipfianConcurrentItems(t *testing.T) {
+	z := NewZipfianWithItems(1000, ZipfianConstant)
+
+	// The threads see different item counts, like the threads which insert with a basis.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(i)))
+			for j := int64(0); j < 10000; j++ {
+				if v := z.next(r, 1000+j*int64(i%4)); v < 0 || v >= 1000+10000*3 {
+					t.Errorf("want in [0, 31000), but got %d", v)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s := z.state.Load().(*zipfianState)
+	if s.items != 1000+9999*3 {
+		t.Fatalf("want %d items, but got %d", 1000+9999*3, s.items)
+	}
+	if want := zetaStatic(0, s.items, ZipfianConstant, 0); math.Abs(s.zetan-want) > 1e-9*want {
+		t.Fatalf("want zetan %v, but got %v", want, s.zetan)
+	}
+
+	// The items never go backward.
+	r := rand.New(rand.NewSource(1))
+	z.next(r, 1000)
+	if items := z.state.Load().(*zipfianState).items; items != s.items {
+		t.Fatalf("want %d items, but got %d", s.items, items)
+	}
+}
@@ -31,6 +31,7 @@ type histogram struct {
 	boundInterval int64
 	count         int64
 	sum           int64
+	bytes         int64
 	min           int64
 	max           int64
 	startTime     time.Time
@@ -51,6 +52,9 @@ const (
 	PER99TH                 = "PER99TH"
 	PER999TH                = "PER999TH"
 	PER9999TH               = "PER9999TH"
+	BYTES                   = "BYTES"
+	THROUGHPUT              = "THROUGHPUT"
+	AVGSIZE                 = "AVGSIZE"
 )
 
 func (h *histogram) Info() ycsb.MeasurementInfo {
@@ -105,6 +109,10 @@ func (h *histogram) Measure(latency time.Duration) {
 	}
 }
 
+func (h *histogram) MeasureBytes(bytes int64) {
+	atomic.AddInt64(&h.bytes, bytes)
+}
+
 func (h *histogram) Summary() string {
 	res := h.getInfo()
 
@@ -112,6 +120,10 @@ func (h *histogram) Summary() string {
 	buf.WriteString(fmt.Sprintf("Takes(s): %.1f, ", res[ELAPSED]))
 	buf.WriteString(fmt.Sprintf("Count: %d, ", res[COUNT]))
 	buf.WriteString(fmt.Sprintf("OPS: %.1f, ", res[QPS]))
+	if res[BYTES].(int64) > 0 {
+		buf.WriteString(fmt.Sprintf("Throughput(MB/s): %.2f, ", res[THROUGHPUT]))
+		buf.WriteString(fmt.Sprintf("AvgSize(B): %d, ", res[AVGSIZE]))
+	}
 	buf.WriteString(fmt.Sprintf("Avg(us): %d, ", res[AVG]))
 	buf.WriteString(fmt.Sprintf("Min(us): %d, ", res[MIN]))
 	buf.WriteString(fmt.Sprintf("Max(us): %d, ", res[MAX]))
@@ -127,6 +139,7 @@ func (h *histogram) getInfo() map[string]interface{} {
 	max := atomic.LoadInt64(&h.max)
 	sum := atomic.LoadInt64(&h.sum)
 	count := atomic.LoadInt64(&h.count)
+	bytes := atomic.LoadInt64(&h.bytes)
 
 	bounds := h.boundCounts.Keys()
 	sort.Ints(bounds)
@@ -160,6 +173,12 @@ func (h *histogram) getInfo() map[string]interface{} {
 	res[ELAPSED] = elapsed
 	res[COUNT] = count
 	res[QPS] = qps
+	res[BYTES] = bytes
+	res[THROUGHPUT] = float64(bytes) / elapsed / (1 << 20)
+	res[AVGSIZE] = int64(0)
+	if count > 0 {
+		res[AVGSIZE] = bytes / count
+	}
 	res[AVG] = avg
 	res[MIN] = min
 	res[MAX] = max
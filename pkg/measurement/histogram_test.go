@@ -0,0 +1,38 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurement
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties"
+)
+
+func TestHistogramSummary(t *testing.T) {
+	h := newHistogram(properties.NewProperties())
+	if s := h.Summary(); strings.Contains(s, "MB/s") {
+		t.Fatalf("want no throughput without bytes, but got %s", s)
+	}
+
+	for _, bytes := range []int64{100, 300} {
+		h.Measure(time.Millisecond)
+		h.MeasureBytes(bytes)
+	}
+	s := h.Summary()
+	if !strings.Contains(s, "Count: 2, ") || !strings.Contains(s, "Throughput(MB/s): ") || !strings.Contains(s, "AvgSize(B): 200, ") {
+		t.Fatalf("want the throughput and the average size 200, but got %s", s)
+	}
+}
@@ -33,19 +33,31 @@ type measurement struct {
 	opMeasurement map[string]ycsb.Measurement
 }
 
-func (m *measurement) measure(op string, lan time.Duration) {
+func (m *measurement) getOpMeasurement(op string) ycsb.Measurement {
 	m.RLock()
 	opM, ok := m.opMeasurement[op]
 	m.RUnlock()
 
 	if !ok {
-		opM = newHistogram(m.p)
 		m.Lock()
-		m.opMeasurement[op] = opM
+		if opM, ok = m.opMeasurement[op]; !ok {
+			opM = newHistogram(m.p)
+			m.opMeasurement[op] = opM
+		}
 		m.Unlock()
 	}
 
+	return opM
+}
+
+func (m *measurement) measure(op string, lan time.Duration) {
+	m.getOpMeasurement(op).Measure(lan)
+}
+
+func (m *measurement) measureBytes(op string, lan time.Duration, bytes int64) {
+	opM := m.getOpMeasurement(op)
 	opM.Measure(lan)
+	opM.MeasureBytes(bytes)
 }
 
 func (m *measurement) output() {
@@ -120,6 +132,13 @@ func Measure(op string, lan time.Duration) {
 	}
 }
 
+// MeasureBytes measures the operation and the payload bytes it transferred.
+func MeasureBytes(op string, lan time.Duration, bytes int64) {
+	if IsWarmUpFinished() {
+		globalMeasure.measureBytes(op, lan, bytes)
+	}
+}
+
 // Info returns all the operations MeasurementInfo.
 // The key of returned map is the operation name.
 func Info() map[string]ycsb.MeasurementInfo {
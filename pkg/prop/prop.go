@@ -13,6 +13,8 @@
 
 package prop
 
+import "time"
+
 // Properties
 const (
 	InsertStart        = "insertstart"
@@ -66,7 +68,25 @@ const (
 	ScanProportionDefault            = float64(0.0)
 	ReadModifyWriteProportion        = "readmodifywriteproportion"
 	ReadModifyWriteProportionDefault = float64(0.0)
-	// "uniform", "zipfian", "latest"
+	// Create a secondary index on field0, used by the index operations below
+	SecondaryIndex              = "secondaryindex"
+	SecondaryIndexDefault       = false
+	IndexReadProportion         = "indexreadproportion"
+	IndexReadProportionDefault  = float64(0.0)
+	IndexScanProportion         = "indexscanproportion"
+	IndexScanProportionDefault  = float64(0.0)
+	IndexCountProportion        = "indexcountproportion"
+	IndexCountProportionDefault = float64(0.0)
+	// The range operations cover about scanlength records when the inserts are ordered
+	ScanRangeProportion          = "scanrangeproportion"
+	ScanRangeProportionDefault   = float64(0.0)
+	DeleteRangeProportion        = "deleterangeproportion"
+	DeleteRangeProportionDefault = float64(0.0)
+	// The generator registered in pkg/generator to mix the operations by the proportions
+	OperationDistribution        = "operationdistribution"
+	OperationDistributionDefault = "discrete"
+	// "uniform", "sequential", "zipfian", "latest", "hotspot", "exponential",
+	// "rotatinghotspot", "reshuffledzipfian", "flashcrowd", "empirical"
 	RequestDistribution        = "requestdistribution"
 	RequestDistributionDefault = "uniform"
 	ZeroPadding                = "zeropadding"
@@ -93,6 +113,41 @@ const (
 	ExponentialFrac              = "exponential.frac"
 	ExponentialFracDefault       = float64(0.8571428571)
 
+	// Used if requestdistribution is "empirical", the file of the rank and access frequency pairs
+	EmpiricalFile        = "empirical.file"
+	EmpiricalFileDefault = "keyfreq.txt"
+
+	// Used if requestdistribution is "rotatinghotspot", the hot set moves shiftfraction
+	// of the data items after every interval. The shift fraction defaults to hotspotdatafraction.
+	HotspotInterval        = "hotspot.interval"
+	HotspotIntervalDefault = time.Minute
+	HotspotShiftFraction   = "hotspot.shiftfraction"
+	// The constant of all the zipfian distributions, in (0, 1)
+	ZipfianConstant        = "zipfian.constant"
+	ZipfianConstantDefault = float64(0.99)
+	// Used if requestdistribution is "reshuffledzipfian"
+	ZipfianShuffleInterval        = "zipfian.shuffleinterval"
+	ZipfianShuffleIntervalDefault = time.Minute
+	// Used if requestdistribution is "flashcrowd", the flash crowd happens at the end of every interval
+	FlashCrowdInterval            = "flashcrowd.interval"
+	FlashCrowdIntervalDefault     = time.Minute
+	FlashCrowdDuration            = "flashcrowd.duration"
+	FlashCrowdDurationDefault     = 10 * time.Second
+	FlashCrowdDataFraction        = "flashcrowd.datafraction"
+	FlashCrowdDataFractionDefault = float64(0.001)
+	FlashCrowdOpnFraction         = "flashcrowd.opnfraction"
+	FlashCrowdOpnFractionDefault  = float64(0.9)
+
+	// The schema file describing the fields, which replaces fieldcount
+	Schema = "schema"
+
+	// "random", "compressible", "json", "text" or "zeros"
+	ValueGenerator        = "valuegenerator"
+	ValueGeneratorDefault = "random"
+	// The target compression ratio of the "compressible" values
+	ValueCompressionRatio        = "valuegenerator.compressionratio"
+	ValueCompressionRatioDefault = float64(2.0)
+
 	DebugPprof        = "debug.pprof"
 	DebugPprofDefault = ":6060"
 
@@ -110,9 +165,46 @@ const (
 	KeyPrefix        = "keyprefix"
 	KeyPrefixDefault = "user"
 	Mock             = "mock"
-	KeyLength        = "key.len"
 	ValueLength      = "val.len"
 
+	// "hostname", "none", "label" or "explicit"
+	KeyNamespace        = "keynamespace"
+	KeyNamespaceDefault = "hostname"
+	KeyNamespaceValue   = "keynamespace.value"
+	// Record the metadata of the loaded data and check it before running
+	Metadata        = "metadata"
+	MetadataDefault = true
+	// The table of the metadata records, <table>_metadata by default
+	MetadataTable = "metadata.table"
+
+	// The file to save the checkpoint of the load periodically
+	CheckpointFile            = "checkpoint.file"
+	CheckpointInterval        = "checkpoint.interval"
+	CheckpointIntervalDefault = 10 * time.Second
+	// Resume the load from the checkpoint file
+	LoadResume        = "load.resume"
+	LoadResumeDefault = false
+	// Insert insertcount records after the loaded records
+	LoadAppend        = "load.append"
+	LoadAppendDefault = false
+
+	// "default", "binary", "uuid4", "uuid7", "timestamp" or "path"
+	KeyFormat         = "keyformat"
+	KeyFormatDefault  = "default"
+	KeyLength         = "keylength"
+	KeyLengthDefault  = 0
+	KeyTenants        = "keyformat.tenants"
+	KeyTenantsDefault = int64(100)
+	KeyUsers          = "keyformat.users"
+	KeyUsersDefault   = int64(1000)
+	// DeprecatedKeyLength was the length which the TiKV txn driver cut the keys to,
+	// replaced by keylength.
+	DeprecatedKeyLength = "key.len"
+
 	Panic        = "panic"
 	PanicDefault = false
+
+	// The seed of the random numbers of the workers, 0 means seeding by the time
+	RandomSeed        = "randomseed"
+	RandomSeedDefault = int64(0)
 )
@@ -14,36 +14,21 @@
 package util
 
 import (
-	"fmt"
 	"sort"
 
 	"github.com/magiconair/properties"
-	"github.com/pingcap/go-ycsb/pkg/prop"
 )
 
 // createFieldIndices is a helper function to create a field -> index mapping
 // for the core workload
-func createFieldIndices(p *properties.Properties) map[string]int64 {
-	fieldCount := p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
-	m := make(map[string]int64, fieldCount)
-	for i := int64(0); i < fieldCount; i++ {
-		field := fmt.Sprintf("field%d", i)
-		m[field] = i
+func createFieldIndices(fields []string) map[string]int64 {
+	m := make(map[string]int64, len(fields))
+	for i, field := range fields {
+		m[field] = int64(i)
 	}
 	return m
 }
 
-// allFields is a helper function to create all fields
-func allFields(p *properties.Properties) []string {
-	fieldCount := p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
-	fields := make([]string, 0, fieldCount)
-	for i := int64(0); i < fieldCount; i++ {
-		field := fmt.Sprintf("field%d", i)
-		fields = append(fields, field)
-	}
-	return fields
-}
-
 // RowCodec is a helper struct to encode and decode TiDB format row
 type RowCodec struct {
 	fieldIndices map[string]int64
@@ -52,9 +37,10 @@ type RowCodec struct {
 
 // NewRowCodec creates the RowCodec
 func NewRowCodec(p *properties.Properties) *RowCodec {
+	fields := LoadSchema(p).FieldNames()
 	return &RowCodec{
-		fieldIndices: createFieldIndices(p),
-		fields:       allFields(p),
+		fieldIndices: createFieldIndices(fields),
+		fields:       fields,
 	}
 }
 
@@ -0,0 +1,206 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+)
+
+// The field types of the schema.
+const (
+	FieldTypeBytes     = "bytes"
+	FieldTypeString    = "string"
+	FieldTypeInt       = "int"
+	FieldTypeTimestamp = "timestamp"
+)
+
+// TimestampLayout is the layout of the timestamp field values, which is accepted by
+// the SQL databases.
+const TimestampLayout = "2006-01-02 15:04:05.000000"
+
+// SchemaField describes a field of the records.
+type SchemaField struct {
+	Name string
+	// Type is one of bytes, string, int and timestamp.
+	Type string
+	// Length is the maximum length of the bytes and string values.
+	Length int64
+	// LengthDistribution is the distribution of the length, like fieldlengthdistribution.
+	LengthDistribution string
+	// Presence is the probability of the field being written in a record.
+	Presence float64
+}
+
+// Schema describes the fields of the records.
+type Schema struct {
+	Fields []SchemaField
+	// Custom is true if the schema is loaded from the schema file, otherwise the
+	// schema is built from fieldcount and fieldlength.
+	Custom bool
+}
+
+// FieldNames returns the names of all the fields.
+func (s *Schema) FieldNames() []string {
+	names := make([]string, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// Field returns the field with the name, or nil if there is no such field.
+func (s *Schema) Field(name string) *SchemaField {
+	for i := range s.Fields {
+		if s.Fields[i].Name == name {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+// MetadataField returns the field which keeps the load metadata, the first bytes or
+// string field, or nil if there is no such field.
+func (s *Schema) MetadataField() *SchemaField {
+	for i := range s.Fields {
+		if s.Fields[i].Type == FieldTypeBytes || s.Fields[i].Type == FieldTypeString {
+			return &s.Fields[i]
+		}
+	}
+	return nil
+}
+
+// MetadataTable returns the table of the load metadata records, or "" if the metadata
+// is disabled.
+func MetadataTable(p *properties.Properties) string {
+	if !p.GetBool(prop.Metadata, prop.MetadataDefault) {
+		return ""
+	}
+	return p.GetString(prop.MetadataTable, p.GetString(prop.TableName, prop.TableNameDefault)+"_metadata")
+}
+
+// ParseSchema parses the schema file. Every line describes a field with its name, type
+// and optional settings like:
+//
+//	# name   type       settings
+//	id       int
+//	created  timestamp
+//	name     string     length=32 distribution=uniform
+//	payload  bytes      length=1024 distribution=zipfian presence=0.3
+//
+// The length is fieldlength by default, the distribution is fieldlengthdistribution by
+// default, and the presence is 1 by default.
+func ParseSchema(p *properties.Properties, data string) (*Schema, error) {
+	s := &Schema{Custom: true}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		items := strings.Fields(line)
+		if len(items) < 2 {
+			return nil, fmt.Errorf("invalid field %q, want name and type", line)
+		}
+
+		f := SchemaField{
+			Name:               items[0],
+			Type:               strings.ToLower(items[1]),
+			Length:             p.GetInt64(prop.FieldLength, prop.FieldLengthDefault),
+			LengthDistribution: p.GetString(prop.FieldLengthDistribution, prop.FieldLengthDistributionDefault),
+			Presence:           1,
+		}
+		switch f.Type {
+		case FieldTypeBytes, FieldTypeString, FieldTypeInt, FieldTypeTimestamp:
+		default:
+			return nil, fmt.Errorf("unknown type %s of field %s", items[1], f.Name)
+		}
+		if s.Field(f.Name) != nil {
+			return nil, fmt.Errorf("duplicate field %s", f.Name)
+		}
+
+		for _, item := range items[2:] {
+			kv := strings.SplitN(item, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid setting %q of field %s", item, f.Name)
+			}
+
+			var err error
+			switch kv[0] {
+			case "length":
+				f.Length, err = strconv.ParseInt(kv[1], 10, 64)
+				if err == nil && f.Length <= 0 {
+					err = fmt.Errorf("must be positive")
+				}
+			case "distribution":
+				f.LengthDistribution = kv[1]
+			case "presence":
+				f.Presence, err = strconv.ParseFloat(kv[1], 64)
+				if err == nil && (f.Presence < 0 || f.Presence > 1) {
+					err = fmt.Errorf("must be in [0, 1]")
+				}
+			default:
+				err = fmt.Errorf("unknown setting")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid setting %q of field %s: %v", item, f.Name, err)
+			}
+		}
+
+		s.Fields = append(s.Fields, f)
+	}
+
+	if len(s.Fields) == 0 {
+		return nil, fmt.Errorf("no field is defined")
+	}
+	return s, nil
+}
+
+// LoadSchema loads the schema file set by the schema property, or builds the schema
+// of fieldcount string fields named field0, field1, ... if the property is not set.
+func LoadSchema(p *properties.Properties) *Schema {
+	name := p.GetString(prop.Schema, "")
+	if len(name) == 0 {
+		fieldCount := p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
+		fieldLength := p.GetInt64(prop.FieldLength, prop.FieldLengthDefault)
+		fieldLengthDistribution := p.GetString(prop.FieldLengthDistribution, prop.FieldLengthDistributionDefault)
+
+		s := &Schema{Fields: make([]SchemaField, 0, fieldCount)}
+		for i := int64(0); i < fieldCount; i++ {
+			s.Fields = append(s.Fields, SchemaField{
+				Name:               fmt.Sprintf("field%d", i),
+				Type:               FieldTypeString,
+				Length:             fieldLength,
+				LengthDistribution: fieldLengthDistribution,
+				Presence:           1,
+			})
+		}
+		return s
+	}
+
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		Fatalf("load schema file %s failed %v", name, err)
+	}
+	s, err := ParseSchema(p, string(data))
+	if err != nil {
+		Fatalf("parse schema file %s failed %v", name, err)
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/magiconair/properties"
+)
+
+func TestParseSchema(t *testing.T) {
+	p := properties.MustLoadString("fieldlength=50\nfieldlengthdistribution=uniform")
+	s, err := ParseSchema(p, `
+# name   type       settings
+id       int
+created  TIMESTAMP
+name     string     length=32 distribution=constant
+payload  bytes      presence=0.3
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := []SchemaField{
+		{Name: "id", Type: FieldTypeInt, Length: 50, LengthDistribution: "uniform", Presence: 1},
+		{Name: "created", Type: FieldTypeTimestamp, Length: 50, LengthDistribution: "uniform", Presence: 1},
+		{Name: "name", Type: FieldTypeString, Length: 32, LengthDistribution: "constant", Presence: 1},
+		{Name: "payload", Type: FieldTypeBytes, Length: 50, LengthDistribution: "uniform", Presence: 0.3},
+	}
+	if !reflect.DeepEqual(s.Fields, check) {
+		t.Fatalf("want %v, but got %v", check, s.Fields)
+	}
+	if names := s.FieldNames(); !reflect.DeepEqual(names, []string{"id", "created", "name", "payload"}) {
+		t.Fatalf("unexpected field names %v", names)
+	}
+
+	for _, data := range []string{
+		"",
+		"id",
+		"id float",
+		"id int\nid string",
+		"name string length=0",
+		"name string presence=2",
+		"name string size=10",
+	} {
+		if _, err = ParseSchema(p, data); err == nil {
+			t.Fatalf("want an error for schema %q", data)
+		}
+	}
+}
+
+func TestDefaultSchema(t *testing.T) {
+	p := properties.MustLoadString("fieldcount=2\nfieldlength=10")
+	s := LoadSchema(p)
+	if s.Custom {
+		t.Fatal("want the default schema")
+	}
+
+	check := []SchemaField{
+		{Name: "field0", Type: FieldTypeString, Length: 10, LengthDistribution: "constant", Presence: 1},
+		{Name: "field1", Type: FieldTypeString, Length: 10, LengthDistribution: "constant", Presence: 1},
+	}
+	if !reflect.DeepEqual(s.Fields, check) {
+		t.Fatalf("want %v, but got %v", check, s.Fields)
+	}
+}
+
+func TestMetadataField(t *testing.T) {
+	p := properties.MustLoadString("fieldlength=50")
+	s, err := ParseSchema(p, "id int\ncreated timestamp\npayload bytes\nname string")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := s.MetadataField(); f == nil || f.Name != "payload" {
+		t.Fatalf("want the metadata field payload, but got %v", f)
+	}
+	if s, err = ParseSchema(p, "id int"); err != nil {
+		t.Fatal(err)
+	}
+	if f := s.MetadataField(); f != nil {
+		t.Fatalf("want no metadata field, but got %v", f)
+	}
+
+	for s, want := range map[string]string{
+		"":                 "usertable_metadata",
+		"table=t":          "t_metadata",
+		"metadata.table=m": "m",
+		"metadata=false":   "",
+	} {
+		if table := MetadataTable(properties.MustLoadString(s)); table != want {
+			t.Fatalf("want the metadata table %q of %q, but got %q", want, s, table)
+		}
+	}
+}
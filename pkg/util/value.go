@@ -0,0 +1,168 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+)
+
+// ValueGenerator generates the content of the values.
+type ValueGenerator interface {
+	// Fill fills the whole buffer with the generated content.
+	Fill(r *rand.Rand, b []byte)
+}
+
+// NewValueGenerator creates the ValueGenerator chosen by the valuegenerator property.
+func NewValueGenerator(p *properties.Properties) (ValueGenerator, error) {
+	name := p.GetString(prop.ValueGenerator, prop.ValueGeneratorDefault)
+	switch strings.ToLower(name) {
+	case "random":
+		return randomValue{}, nil
+	case "compressible":
+		ratio := p.GetFloat64(prop.ValueCompressionRatio, prop.ValueCompressionRatioDefault)
+		if ratio < 1 {
+			return nil, fmt.Errorf("the compression ratio %v must not be less than 1", ratio)
+		}
+		return compressibleValue{ratio: ratio}, nil
+	case "json":
+		return jsonValue{}, nil
+	case "text":
+		return textValue{}, nil
+	case "zeros":
+		return zerosValue{}, nil
+	default:
+		return nil, fmt.Errorf("unknown value generator %s", name)
+	}
+}
+
+// randomValue fills random alphabetic characters, which are hardly compressible.
+type randomValue struct{}
+
+func (randomValue) Fill(r *rand.Rand, b []byte) {
+	RandBytes(r, b)
+}
+
+// compressibleValue fills random characters for 1/ratio of the value and repeats them
+// to the end, like the compressible values of RocksDB db_bench, so LZ compressors like
+// snappy and lz4 shrink the value by about the ratio.
+type compressibleValue struct {
+	ratio float64
+}
+
+func (c compressibleValue) Fill(r *rand.Rand, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+
+	n := int(math.Ceil(float64(len(b)) / c.ratio))
+	RandBytes(r, b[:n])
+	for i := n; i < len(b); i += n {
+		copy(b[i:], b[:n])
+	}
+}
+
+var words = []string{
+	"the", "of", "and", "to", "in", "is", "was", "for", "that", "with",
+	"as", "on", "by", "at", "from", "this", "which", "or", "are", "be",
+	"data", "time", "system", "value", "record", "table", "key", "store", "read", "write",
+	"server", "client", "request", "cluster", "region", "node", "disk", "memory", "cache", "index",
+	"query", "user", "order", "item", "price", "account", "message", "event", "status", "update",
+	"new", "first", "last", "large", "small", "fast", "slow", "high", "low", "long",
+	"city", "street", "name", "email",
+}
+
+// fillText fills space-separated words, the last word may be truncated.
+func fillText(r *rand.Rand, b []byte) {
+	for i := 0; i < len(b); {
+		if i > 0 {
+			b[i] = ' '
+			i++
+		}
+		i += copy(b[i:], words[r.Intn(len(words))])
+	}
+}
+
+// textValue fills English-like words, which compress like natural text.
+type textValue struct{}
+
+func (textValue) Fill(r *rand.Rand, b []byte) {
+	fillText(r, b)
+}
+
+// jsonValue fills a JSON document with nested fields, and pads the "note" field with
+// text to the value size. Values shorter than 11 bytes can't hold a document and are
+// filled with text.
+type jsonValue struct{}
+
+const (
+	jsonNote    = `"note":"`
+	jsonNoteEnd = `"}`
+)
+
+func (jsonValue) Fill(r *rand.Rand, b []byte) {
+	var head [256]byte
+	h := append(head[:0], `{"id":`...)
+	h = strconv.AppendInt(h, r.Int63(), 10)
+	h = append(h, `,"name":"`...)
+	h = append(h, words[r.Intn(len(words))]...)
+	h = append(h, ' ')
+	h = append(h, words[r.Intn(len(words))]...)
+	h = append(h, `","active":`...)
+	h = strconv.AppendBool(h, r.Intn(2) == 0)
+	h = append(h, `,"address":{"street":"`...)
+	h = strconv.AppendInt(h, int64(r.Intn(1000)), 10)
+	h = append(h, ' ')
+	h = append(h, words[r.Intn(len(words))]...)
+	h = append(h, `","city":"`...)
+	h = append(h, words[r.Intn(len(words))]...)
+	h = append(h, `","zip":"`...)
+	h = append(h, fmt.Sprintf("%05d", r.Intn(100000))...)
+	h = append(h, `"},"tags":["`...)
+	h = append(h, words[r.Intn(len(words))]...)
+	h = append(h, `","`...)
+	h = append(h, words[r.Intn(len(words))]...)
+	h = append(h, `"],`...)
+	h = append(h, jsonNote...)
+
+	if len(h)+len(jsonNoteEnd) > len(b) {
+		h = append(head[:0], '{')
+		h = append(h, jsonNote...)
+	}
+	if len(h)+len(jsonNoteEnd) > len(b) {
+		fillText(r, b)
+		return
+	}
+
+	n := copy(b, h)
+	end := len(b) - len(jsonNoteEnd)
+	fillText(r, b[n:end])
+	copy(b[end:], jsonNoteEnd)
+}
+
+// zerosValue fills zero bytes, which compress best. Note that some databases don't
+// allow zero bytes in the string columns.
+type zerosValue struct{}
+
+func (zerosValue) Fill(r *rand.Rand, b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+)
+
+func newTestValueGenerator(t *testing.T, name string) ValueGenerator {
+	p := properties.NewProperties()
+	p.Set(prop.ValueGenerator, name)
+	p.Set(prop.ValueCompressionRatio, "4")
+	g, err := NewValueGenerator(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func compressedSize(t *testing.T, b []byte) int {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(b)
+	w.Close()
+	return buf.Len()
+}
+
+func TestValueGenerator(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, name := range []string{"random", "compressible", "json", "text", "zeros"} {
+		g := newTestValueGenerator(t, name)
+		for _, size := range []int{0, 1, 10, 11, 100, 4096} {
+			b := bytes.Repeat([]byte{0xff}, size)
+			g.Fill(r, b)
+			if bytes.IndexByte(b, 0xff) >= 0 {
+				t.Fatalf("%s value of size %d is not filled: %q", name, size, b)
+			}
+		}
+	}
+
+	if _, err := NewValueGenerator(properties.MustLoadString("valuegenerator=unknown")); err == nil {
+		t.Fatal("want an error for the unknown value generator")
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	g := newTestValueGenerator(t, "json")
+	for _, size := range []int{11, 12, 50, 100, 1000} {
+		b := make([]byte, size)
+		g.Fill(r, b)
+		var doc map[string]interface{}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			t.Fatalf("invalid json of size %d %q: %v", size, b, err)
+		}
+		if size >= 1000 {
+			if _, ok := doc["address"].(map[string]interface{}); !ok {
+				t.Fatalf("want nested address in %q", b)
+			}
+		}
+	}
+}
+
+func TestValueCompressionRatio(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	b := make([]byte, 16384)
+
+	newTestValueGenerator(t, "random").Fill(r, b)
+	random := compressedSize(t, b)
+
+	newTestValueGenerator(t, "compressible").Fill(r, b)
+	compressible := compressedSize(t, b)
+
+	newTestValueGenerator(t, "text").Fill(r, b)
+	text := compressedSize(t, b)
+
+	if ratio := float64(random) / float64(compressible); ratio < 3.5 {
+		t.Fatalf("want the compressible values at least 4 times smaller than the random ones, but got %v", ratio)
+	}
+	if text >= random {
+		t.Fatalf("want text compressed to less than %d, but got %d", random, text)
+	}
+}
@@ -19,10 +19,10 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/magiconair/properties"
@@ -41,8 +41,17 @@ type coreState struct {
 	r *rand.Rand
 	// fieldNames is a copy of core.fieldNames to be goroutine-local
 	fieldNames []string
+	// pendingInsert is the first key number not inserted by the thread, or noPendingInsert
+	// if all the keys are inserted. It's read by Checkpoint atomically.
+	pendingInsert int64
+	// failedInsert is true if an insert of the load fails, then pendingInsert stops at the
+	// failed key for the rest of the load. The failed key is never inserted again, so the
+	// checkpoint can't pass it.
+	failedInsert bool
 }
 
+const noPendingInsert = math.MaxInt64
+
 type operationType int64
 
 const (
@@ -51,99 +60,165 @@ const (
 	insert
 	scan
 	readModifyWrite
+	indexRead
+	indexScan
+	indexCount
+	scanRange
+	deleteRange
 )
 
+// indexField is the field indexed when the secondary index is enabled. Its value is
+// always derived from the key, so records can be looked up by it.
+const indexField = "field0"
+
+// field is a field of the records, described by the schema.
+type field struct {
+	util.SchemaField
+	lengthGenerator ycsb.Generator
+}
+
 // Core is the core benchmark scenario. Represents a set of clients doing simple CRUD operations.
 type core struct {
 	p *properties.Properties
 
-	table      string
-	fieldCount int64
-	fieldNames []string
+	table        string
+	fieldCount   int64
+	fieldNames   []string
+	fields       []field
+	fieldIndices map[string]int
 
-	fieldLengthGenerator ycsb.Generator
-	readAllFields        bool
-	writeAllFields       bool
-	dataIntegrity        bool
+	valueGenerator util.ValueGenerator
+	readAllFields  bool
+	writeAllFields bool
+	dataIntegrity  bool
+	secondaryIndex bool
+	indexOnce      sync.Once
+	indexErr       error
 
 	keySequence                  ycsb.Generator
-	operationChooser             *generator.Discrete
+	operationChooser             ycsb.Generator
 	keyChooser                   ycsb.Generator
 	fieldChooser                 ycsb.Generator
 	transactionInsertKeySequence *generator.AcknowledgedCounter
+	insertKeyStart               int64
 	scanLength                   ycsb.Generator
 	orderedInserts               bool
+	keyNamespace                 string
+	keys                         *keyBuilder
 	recordCount                  int64
-	zeroPadding                  int64
+	seed                         int64
 	insertionRetryLimit          int64
 	insertionRetryInterval       int64
-	keyDefault                   string
+
+	// The load inserts the keys in [insertStart, insertEnd).
+	insertStart int64
+	insertEnd   int64
+	appendLoad  bool
+	states      []*coreState
+	statesMu    sync.Mutex
+
+	// maxInsertKey is the max key number inserted successfully, updated atomically.
+	maxInsertKey int64
+	// loadMetadata is the metadata recorded by the load, which the run updates.
+	loadMetadata map[string]string
 
 	valuePool sync.Pool
 }
 
 func getFieldLengthGenerator(p *properties.Properties) ycsb.Generator {
-	var fieldLengthGenerator ycsb.Generator
 	fieldLengthDistribution := p.GetString(prop.FieldLengthDistribution, prop.FieldLengthDistributionDefault)
 	fieldLength := p.GetInt64(prop.FieldLength, prop.FieldLengthDefault)
 	fieldLengthHistogram := p.GetString(prop.FieldLengthHistogramFile, prop.FieldLengthHistogramFileDefault)
 
-	switch strings.ToLower(fieldLengthDistribution) {
-	case "constant":
-		fieldLengthGenerator = generator.NewConstant(fieldLength)
-	case "uniform":
-		fieldLengthGenerator = generator.NewUniform(1, fieldLength)
-	case "zipfian":
-		fieldLengthGenerator = generator.NewZipfianWithRange(1, fieldLength, generator.ZipfianConstant)
-	case "histogram":
-		fieldLengthGenerator = generator.NewHistogramFromFile(fieldLengthHistogram)
-	default:
-		util.Fatalf("unknown field length distribution %s", fieldLengthDistribution)
+	fieldLengthGenerator, err := generator.New(fieldLengthDistribution, p, generator.Args{
+		Min:           1,
+		Max:           fieldLength,
+		HistogramFile: fieldLengthHistogram,
+	})
+	if err != nil {
+		util.Fatalf("create field length generator failed %v", err)
 	}
 
 	return fieldLengthGenerator
 }
 
-func createOperationGenerator(p *properties.Properties) *generator.Discrete {
-	readProportion := p.GetFloat64(prop.ReadProportion, prop.ReadProportionDefault)
-	updateProportion := p.GetFloat64(prop.UpdateProportion, prop.UpdateProportionDefault)
-	insertProportion := p.GetFloat64(prop.InsertProportion, prop.InsertProportionDefault)
-	scanProportion := p.GetFloat64(prop.ScanProportion, prop.ScanProportionDefault)
-	readModifyWriteProportion := p.GetFloat64(prop.ReadModifyWriteProportion, prop.ReadModifyWriteProportionDefault)
+// createFields creates the fields described by the schema. Without the schema file, all
+// the fields share the length generator of fieldlengthdistribution.
+func (c *core) createFields(p *properties.Properties) {
+	schema := util.LoadSchema(p)
+	fieldLengthHistogram := p.GetString(prop.FieldLengthHistogramFile, prop.FieldLengthHistogramFileDefault)
 
-	operationChooser := generator.NewDiscrete()
-	if readProportion > 0 {
-		operationChooser.Add(readProportion, int64(read))
+	var fieldLengthGenerator ycsb.Generator
+	if !schema.Custom {
+		fieldLengthGenerator = getFieldLengthGenerator(p)
 	}
 
-	if updateProportion > 0 {
-		operationChooser.Add(updateProportion, int64(update))
-	}
+	c.fieldCount = int64(len(schema.Fields))
+	c.fieldNames = schema.FieldNames()
+	c.fields = make([]field, 0, len(schema.Fields))
+	c.fieldIndices = make(map[string]int, len(schema.Fields))
+	for i, f := range schema.Fields {
+		lengthGenerator := fieldLengthGenerator
+		if lengthGenerator == nil {
+			var err error
+			lengthGenerator, err = generator.New(f.LengthDistribution, p, generator.Args{
+				Min:           1,
+				Max:           f.Length,
+				HistogramFile: fieldLengthHistogram,
+			})
+			if err != nil {
+				util.Fatalf("create length generator of field %s failed %v", f.Name, err)
+			}
+		}
 
-	if insertProportion > 0 {
-		operationChooser.Add(insertProportion, int64(insert))
+		c.fields = append(c.fields, field{SchemaField: f, lengthGenerator: lengthGenerator})
+		c.fieldIndices[f.Name] = i
 	}
+}
 
-	if scanProportion > 0 {
-		operationChooser.Add(scanProportion, int64(scan))
+func createOperationGenerator(p *properties.Properties) ycsb.Generator {
+	weights := map[int64]float64{
+		int64(read):            p.GetFloat64(prop.ReadProportion, prop.ReadProportionDefault),
+		int64(update):          p.GetFloat64(prop.UpdateProportion, prop.UpdateProportionDefault),
+		int64(insert):          p.GetFloat64(prop.InsertProportion, prop.InsertProportionDefault),
+		int64(scan):            p.GetFloat64(prop.ScanProportion, prop.ScanProportionDefault),
+		int64(readModifyWrite): p.GetFloat64(prop.ReadModifyWriteProportion, prop.ReadModifyWriteProportionDefault),
+		int64(indexRead):       p.GetFloat64(prop.IndexReadProportion, prop.IndexReadProportionDefault),
+		int64(indexScan):       p.GetFloat64(prop.IndexScanProportion, prop.IndexScanProportionDefault),
+		int64(indexCount):      p.GetFloat64(prop.IndexCountProportion, prop.IndexCountProportionDefault),
+		int64(scanRange):       p.GetFloat64(prop.ScanRangeProportion, prop.ScanRangeProportionDefault),
+		int64(deleteRange):     p.GetFloat64(prop.DeleteRangeProportion, prop.DeleteRangeProportionDefault),
 	}
 
-	if readModifyWriteProportion > 0 {
-		operationChooser.Add(readModifyWriteProportion, int64(readModifyWrite))
+	operationChooser, err := generator.New(p.GetString(prop.OperationDistribution, prop.OperationDistributionDefault), p, generator.Args{
+		Min:     int64(read),
+		Max:     int64(deleteRange),
+		Weights: weights,
+	})
+	if err != nil {
+		util.Fatalf("create operation generator failed %v", err)
 	}
-
 	return operationChooser
 }
 
 // InitThread implements the Workload InitThread interface.
-func (c *core) InitThread(ctx context.Context, _ int, _ int) context.Context {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+func (c *core) InitThread(ctx context.Context, threadID int, _ int) context.Context {
+	seed := time.Now().UnixNano()
+	if c.seed != 0 {
+		seed = c.seed + int64(threadID)
+	}
+	r := rand.New(rand.NewSource(seed))
 	fieldNames := make([]string, len(c.fieldNames))
 	copy(fieldNames, c.fieldNames)
 	state := &coreState{
-		r:          r,
-		fieldNames: fieldNames,
+		r:             r,
+		fieldNames:    fieldNames,
+		pendingInsert: noPendingInsert,
 	}
+
+	c.statesMu.Lock()
+	c.states = append(c.states, state)
+	c.statesMu.Unlock()
 	return context.WithValue(ctx, stateKey, state)
 }
 
@@ -157,34 +232,283 @@ func (c *core) Close() error {
 	return nil
 }
 
-func (c *core) buildKeyName(ctx context.Context, keyNum int64) string {
-	if !c.orderedInserts {
-		keyNum = util.Hash64(keyNum)
+// The metadata names of the core workload.
+const (
+	metadataKeyNamespace = "keynamespace"
+	metadataKeyFormat    = "keyformat"
+	metadataKeyPrefix    = "keyprefix"
+	metadataKeyLength    = "keylength"
+	metadataZeroPadding  = "zeropadding"
+	metadataInsertOrder  = "insertorder"
+	metadataFields       = "fields"
+	metadataFieldLengths = "fieldlengths"
+	metadataRecordCount  = "recordcount"
+	metadataSeed         = "seed"
+	metadataMaxInsertKey = "maxinsertkey"
+)
+
+// keyMetadata is the metadata deciding the keys and the fields, which must not change
+// after the load, otherwise the run can't find the data. The values are the properties
+// to set.
+var keyMetadata = []struct {
+	name     string
+	property string
+}{
+	{metadataKeyNamespace, prop.KeyNamespace},
+	{metadataKeyFormat, prop.KeyFormat},
+	{metadataKeyPrefix, prop.KeyPrefix},
+	{metadataKeyLength, prop.KeyLength},
+	{metadataZeroPadding, prop.ZeroPadding},
+	{metadataInsertOrder, prop.InsertOrder},
+	{metadataFields, prop.Schema},
+}
+
+// metadataRecordKey is the key of the metadata record, followed by the key namespace.
+const metadataRecordKey = "core"
+
+// MetadataKey implements the MetadataWorkload MetadataKey interface. Every key namespace
+// has its own metadata.
+func (c *core) MetadataKey() string {
+	if len(c.keyNamespace) == 0 {
+		return metadataRecordKey
+	}
+	return metadataRecordKey + "/" + c.keyNamespace
+}
+
+// Metadata implements the MetadataWorkload Metadata interface.
+func (c *core) Metadata() map[string]string {
+	if c.loadMetadata != nil {
+		// The run and the appending load keep the shape of the loaded data, and record
+		// the inserted keys, so the next run resumes inserting after them.
+		m := make(map[string]string, len(c.loadMetadata))
+		for k, v := range c.loadMetadata {
+			m[k] = v
+		}
+		maxInsertKey := atomic.LoadInt64(&c.maxInsertKey)
+		if last := c.transactionInsertKeySequence.Last(); last >= c.insertKeyStart && last > maxInsertKey {
+			maxInsertKey = last
+		}
+		m[metadataRecordCount] = strconv.FormatInt(c.recordCount, 10)
+		m[metadataMaxInsertKey] = strconv.FormatInt(maxInsertKey, 10)
+		return m
+	}
+
+	fields := make([]string, 0, len(c.fields))
+	fieldLengths := make([]string, 0, len(c.fields))
+	for _, f := range c.fields {
+		fields = append(fields, f.Name+":"+f.Type)
+		fieldLengths = append(fieldLengths, strconv.FormatInt(f.Length, 10))
+	}
+
+	return map[string]string{
+		metadataKeyNamespace: c.keyNamespace,
+		metadataKeyFormat:    c.keys.format,
+		metadataKeyPrefix:    c.p.GetString(prop.KeyPrefix, prop.KeyPrefixDefault),
+		metadataKeyLength:    strconv.Itoa(c.keys.length),
+		metadataZeroPadding:  strconv.FormatInt(c.keys.zeroPadding, 10),
+		metadataInsertOrder:  c.p.GetString(prop.InsertOrder, prop.InsertOrderDefault),
+		metadataFields:       strings.Join(fields, ","),
+		metadataFieldLengths: strings.Join(fieldLengths, ","),
+		metadataRecordCount:  strconv.FormatInt(c.recordCount, 10),
+		metadataSeed:         strconv.FormatInt(c.seed, 10),
+		metadataMaxInsertKey: strconv.FormatInt(atomic.LoadInt64(&c.maxInsertKey), 10),
+	}
+}
+
+// MergeMetadata implements the MetadataWorkload MergeMetadata interface. The clients
+// sharing the key namespace load the parts of the same data, so the merged record count
+// and inserted keys are the largest ones.
+func (c *core) MergeMetadata(old map[string]string, m map[string]string) (map[string]string, error) {
+	for _, k := range keyMetadata {
+		if v, ok := old[k.name]; ok && v != m[k.name] {
+			return nil, fmt.Errorf("the data is loaded with %s %q, but this client uses %q", k.name, v, m[k.name])
+		}
+	}
+
+	merged := make(map[string]string, len(m))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for _, name := range []string{metadataRecordCount, metadataMaxInsertKey} {
+		n, err := parseMetadataInt(m, name, -1)
+		if err != nil {
+			return nil, err
+		}
+		oldN, err := parseMetadataInt(old, name, -1)
+		if err != nil {
+			return nil, err
+		}
+		if oldN > n {
+			merged[name] = strconv.FormatInt(oldN, 10)
+		}
+	}
+	return merged, nil
+}
+
+// CheckMetadata implements the MetadataWorkload CheckMetadata interface. The run fails
+// if the keys or the fields differ from the load, and takes the inserted keys of the
+// loaded data, and the record count unless it is set. The appending load inserts after
+// the loaded keys.
+func (c *core) CheckMetadata(m map[string]string) error {
+	own := c.Metadata()
+	for _, k := range keyMetadata {
+		if v, ok := m[k.name]; ok && v != own[k.name] {
+			return fmt.Errorf("the data is loaded with %s %q, but the run uses %q, please set %s",
+				k.name, v, own[k.name], k.property)
+		}
+	}
+	if v, ok := m[metadataFieldLengths]; ok && v != own[metadataFieldLengths] {
+		fmt.Printf("warning: the data is loaded with field lengths %s, but the run uses %s\n",
+			v, own[metadataFieldLengths])
+	}
+
+	recordCount, err := parseMetadataInt(m, metadataRecordCount, c.recordCount)
+	if err != nil {
+		return err
 	}
-	prefix := c.p.GetString(prop.KeyPrefix, prop.KeyPrefixDefault)
-	prefix = c.keyDefault + "_" + prefix
+	maxInsertKey, err := parseMetadataInt(m, metadataMaxInsertKey, recordCount-1)
+	if err != nil {
+		return err
+	}
+	c.loadMetadata = m
+	atomic.StoreInt64(&c.maxInsertKey, maxInsertKey)
+
+	if c.appendLoad {
+		start := recordCount
+		if maxInsertKey >= start {
+			start = maxInsertKey + 1
+		}
+		c.insertStart, c.insertEnd = start, start+c.insertEnd-c.insertStart
+		c.recordCount = c.insertEnd
+		c.keySequence = generator.NewCounter(c.insertStart)
+		fmt.Printf("append the keys in [%d, %d) to the loaded data\n", c.insertStart, c.insertEnd)
+		return nil
+	}
+
+	if _, ok := c.p.Get(prop.RecordCount); ok && recordCount != c.recordCount {
+		fmt.Printf("warning: the data is loaded with %s %d, but the run uses %d\n",
+			prop.RecordCount, recordCount, c.recordCount)
+	} else if recordCount != c.recordCount {
+		fmt.Printf("warning: the data is loaded with %s %d, use the loaded one\n", prop.RecordCount, recordCount)
+		c.recordCount = recordCount
+	}
+	if maxInsertKey < recordCount-1 {
+		fmt.Printf("warning: only the keys up to %d of %d records are loaded\n", maxInsertKey, recordCount)
+	}
+	return c.createKeyChooser()
+}
+
+func parseMetadataInt(m map[string]string, name string, defaultValue int64) (int64, error) {
+	v, ok := m[name]
+	if !ok {
+		return defaultValue, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q in the metadata", name, v)
+	}
+	return n, nil
+}
+
+// Checkpoint implements the CheckpointWorkload Checkpoint interface.
+func (c *core) Checkpoint() (int64, int64) {
+	// Read the key sequence before the threads, a thread publishes its pending key
+	// before taking the keys from the sequence.
+	next := c.keySequence.Last() + 1
+	c.statesMu.Lock()
+	for _, state := range c.states {
+		if pending := atomic.LoadInt64(&state.pendingInsert); pending < next {
+			next = pending
+		}
+	}
+	c.statesMu.Unlock()
+
+	if next > c.insertEnd {
+		next = c.insertEnd
+	}
+	return next, c.insertEnd
+}
+
+// Resume implements the CheckpointWorkload Resume interface.
+func (c *core) Resume(next int64, end int64) error {
+	if end != c.insertEnd || next < c.insertStart || next > end {
+		return fmt.Errorf("the checkpoint of the keys [%d, %d) doesn't match the keys [%d, %d) to insert",
+			next, end, c.insertStart, c.insertEnd)
+	}
+	c.insertStart = next
+	c.keySequence = generator.NewCounter(next)
+	return nil
+}
+
+// nextInsertKeyNum takes a key number to insert by the load.
+func (c *core) nextInsertKeyNum(state *coreState) int64 {
+	if !state.failedInsert && atomic.LoadInt64(&state.pendingInsert) == noPendingInsert {
+		atomic.StoreInt64(&state.pendingInsert, c.keySequence.Last()+1)
+	}
+	return c.keySequence.Next(state.r)
+}
+
+// acknowledgeInsert records the key number inserted successfully by the load.
+func (c *core) acknowledgeInsert(state *coreState, keyNum int64) {
+	if !state.failedInsert {
+		atomic.StoreInt64(&state.pendingInsert, noPendingInsert)
+	}
+
+	for {
+		max := atomic.LoadInt64(&c.maxInsertKey)
+		if keyNum <= max || atomic.CompareAndSwapInt64(&c.maxInsertKey, max, keyNum) {
+			return
+		}
+	}
+}
+
+// createKeyChooser creates the key chooser of the run, and the sequence of the inserted
+// keys, which starts after the records and the keys inserted before.
+func (c *core) createKeyChooser() error {
+	insertStart := c.p.GetInt64(prop.InsertStart, prop.InsertStartDefault)
+	insertCount := c.p.GetInt64(prop.InsertCount, c.recordCount-insertStart)
+	if c.recordCount < insertStart+insertCount {
+		return fmt.Errorf("record count %d must be bigger than insert start %d + count %d",
+			c.recordCount, insertStart, insertCount)
+	}
+
+	c.insertKeyStart = c.recordCount
+	if maxInsertKey := atomic.LoadInt64(&c.maxInsertKey); maxInsertKey >= c.insertKeyStart {
+		c.insertKeyStart = maxInsertKey + 1
+	}
+	c.transactionInsertKeySequence = generator.NewAcknowledgedCounter(c.insertKeyStart)
+
+	var err error
+	requestDistrib := c.p.GetString(prop.RequestDistribution, prop.RequestDistributionDefault)
+	c.keyChooser, err = generator.New(requestDistrib, c.p, generator.Args{
+		Min:       insertStart,
+		Max:       insertStart + insertCount - 1,
+		Scrambled: true,
+		Basis:     c.transactionInsertKeySequence,
+	})
+	if err != nil {
+		return fmt.Errorf("create request distribution failed %v", err)
+	}
+	return nil
+}
+
+func (c *core) buildKeyName(ctx context.Context, keyNum int64) string {
+	key := c.keys.build(keyNum)
 	if c.p.GetBool(prop.RandomKey, prop.RandomKeyDefault) {
 		state := ctx.Value(stateKey).(*coreState)
 		r := state.r
-		prefix = strconv.Itoa(r.Intn(1000)) + prefix
+		key = strconv.Itoa(r.Intn(1000)) + key
 	}
-	return fmt.Sprintf("%s%0[3]*[2]d", prefix, keyNum, c.zeroPadding)
+	return key
 }
 
 func (c *core) buildSingleValue(state *coreState, key string) map[string][]byte {
 	values := make(map[string][]byte, 1)
 
 	r := state.r
-	fieldKey := state.fieldNames[c.fieldChooser.Next(r)]
-
-	var buf []byte
-	if c.dataIntegrity {
-		buf = c.buildDeterministicValue(state, key, fieldKey)
-	} else {
-		buf = c.buildRandomValue(state)
-	}
+	i := int(c.fieldChooser.Next(r))
 
-	values[fieldKey] = buf
+	values[state.fieldNames[i]] = c.buildValue(state, key, i)
 
 	return values
 }
@@ -192,19 +516,36 @@ func (c *core) buildSingleValue(state *coreState, key string) map[string][]byte
 func (c *core) buildValues(state *coreState, key string) map[string][]byte {
 	values := make(map[string][]byte, c.fieldCount)
 
-	for _, fieldKey := range state.fieldNames {
-		var buf []byte
-		if c.dataIntegrity {
-			buf = c.buildDeterministicValue(state, key, fieldKey)
-		} else {
-			buf = c.buildRandomValue(state)
+	for i, fieldKey := range state.fieldNames {
+		if presence := c.fields[i].Presence; presence < 1 && state.r.Float64() >= presence {
+			continue
 		}
-
-		values[fieldKey] = buf
+		values[fieldKey] = c.buildValue(state, key, i)
 	}
 	return values
 }
 
+func (c *core) buildValue(state *coreState, key string, i int) []byte {
+	fieldKey := state.fieldNames[i]
+	if c.secondaryIndex && fieldKey == indexField {
+		return c.buildIndexValue(key)
+	}
+	if c.dataIntegrity {
+		return c.buildDeterministicValue(state, key, fieldKey)
+	}
+
+	switch c.fields[i].Type {
+	case util.FieldTypeInt:
+		return strconv.AppendInt(c.getValueBuffer(20)[:0], state.r.Int63(), 10)
+	case util.FieldTypeTimestamp:
+		// A time in the last year.
+		t := time.Now().Add(-time.Duration(state.r.Int63n(int64(365 * 24 * time.Hour))))
+		return t.UTC().AppendFormat(c.getValueBuffer(len(util.TimestampLayout))[:0], util.TimestampLayout)
+	default:
+		return c.buildRandomValue(state, &c.fields[i])
+	}
+}
+
 func (c *core) getValueBuffer(size int) []byte {
 	buf := c.valuePool.Get().([]byte)
 	if cap(buf) >= size {
@@ -220,18 +561,28 @@ func (c *core) putValues(values map[string][]byte) {
 	}
 }
 
-func (c *core) buildRandomValue(state *coreState) []byte {
+func (c *core) buildRandomValue(state *coreState, f *field) []byte {
 	// TODO: use pool for the buffer
 	r := state.r
-	buf := c.getValueBuffer(int(c.fieldLengthGenerator.Next(r)))
-	util.RandBytes(r, buf)
+	buf := c.getValueBuffer(int(f.lengthGenerator.Next(r)))
+	c.valueGenerator.Fill(r, buf)
 	return buf
 }
 
 func (c *core) buildDeterministicValue(state *coreState, key string, fieldKey string) []byte {
+	f := &c.fields[c.fieldIndices[fieldKey]]
+	return c.buildDeterministicValueWithSize(key, fieldKey, f.lengthGenerator.Next(state.r))
+}
+
+// buildIndexValue builds the value of the index field, which has the constant field length
+// so it can be rebuilt from the key for lookups.
+func (c *core) buildIndexValue(key string) []byte {
+	f := &c.fields[c.fieldIndices[indexField]]
+	return c.buildDeterministicValueWithSize(key, indexField, f.Length)
+}
+
+func (c *core) buildDeterministicValueWithSize(key string, fieldKey string, size int64) []byte {
 	// TODO: use pool for the buffer
-	r := state.r
-	size := c.fieldLengthGenerator.Next(r)
 	buf := c.getValueBuffer(int(size + 21))
 	b := bytes.NewBuffer(buf[0:0])
 	b.WriteString(key)
@@ -253,18 +604,59 @@ func (c *core) verifyRow(state *coreState, key string, values map[string][]byte)
 	}
 
 	for fieldKey, value := range values {
-		expected := c.buildDeterministicValue(state, key, fieldKey)
+		var expected []byte
+		if c.secondaryIndex && fieldKey == indexField {
+			expected = c.buildIndexValue(key)
+		} else {
+			expected = c.buildDeterministicValue(state, key, fieldKey)
+		}
 		if !bytes.Equal(expected, value) {
 			util.Fatalf("unexpected deterministic value, expect %q, but got %q", expected, value)
 		}
 	}
 }
 
+func getQueryDB(db ycsb.DB) (ycsb.QueryDB, error) {
+	queryDB, ok := db.(ycsb.QueryDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the QueryDB interface", db)
+	}
+	return queryDB, nil
+}
+
+func getRangeDB(db ycsb.DB) (ycsb.RangeDB, error) {
+	rangeDB, ok := db.(ycsb.RangeDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the RangeDB interface", db)
+	}
+	return rangeDB, nil
+}
+
+// createIndex creates the secondary index once before the first operation.
+func (c *core) createIndex(ctx context.Context, db ycsb.DB) error {
+	if !c.secondaryIndex {
+		return nil
+	}
+
+	c.indexOnce.Do(func() {
+		queryDB, err := getQueryDB(db)
+		if err != nil {
+			c.indexErr = err
+			return
+		}
+		c.indexErr = queryDB.CreateIndex(ctx, c.table, indexField)
+	})
+	return c.indexErr
+}
+
 // DoInsert implements the Workload DoInsert interface.
 func (c *core) DoInsert(ctx context.Context, db ycsb.DB) error {
+	if err := c.createIndex(ctx, db); err != nil {
+		return err
+	}
 	state := ctx.Value(stateKey).(*coreState)
 	r := state.r
-	keyNum := c.keySequence.Next(r)
+	keyNum := c.nextInsertKeyNum(state)
 	dbKey := c.buildKeyName(ctx, keyNum)
 	values := c.buildValues(state, dbKey)
 	defer c.putValues(values)
@@ -275,6 +667,7 @@ func (c *core) DoInsert(ctx context.Context, db ycsb.DB) error {
 	for {
 		err = db.Insert(ctx, c.table, dbKey, values)
 		if err == nil {
+			c.acknowledgeInsert(state, keyNum)
 			break
 		}
 
@@ -300,6 +693,9 @@ func (c *core) DoInsert(ctx context.Context, db ycsb.DB) error {
 		time.Sleep(time.Duration(sleepTimeMs) * time.Millisecond)
 	}
 
+	if err != nil {
+		state.failedInsert = true
+	}
 	return err
 }
 
@@ -309,12 +705,19 @@ func (c *core) DoBatchInsert(ctx context.Context, batchSize int, db ycsb.DB) err
 	if !ok {
 		return fmt.Errorf("the %T does't implement the batchDB interface", db)
 	}
+	if err := c.createIndex(ctx, db); err != nil {
+		return err
+	}
 	state := ctx.Value(stateKey).(*coreState)
 	r := state.r
 	var keys []string
 	var values []map[string][]byte
+	maxKeyNum := int64(-1)
 	for i := 0; i < batchSize; i++ {
-		keyNum := c.keySequence.Next(r)
+		keyNum := c.nextInsertKeyNum(state)
+		if keyNum > maxKeyNum {
+			maxKeyNum = keyNum
+		}
 		dbKey := c.buildKeyName(ctx, keyNum)
 		keys = append(keys, dbKey)
 		values = append(values, c.buildValues(state, dbKey))
@@ -330,6 +733,7 @@ func (c *core) DoBatchInsert(ctx context.Context, batchSize int, db ycsb.DB) err
 	for {
 		err = batchDB.BatchInsert(ctx, c.table, keys, values)
 		if err == nil {
+			c.acknowledgeInsert(state, maxKeyNum)
 			break
 		}
 
@@ -354,11 +758,17 @@ func (c *core) DoBatchInsert(ctx context.Context, batchSize int, db ycsb.DB) err
 
 		time.Sleep(time.Duration(sleepTimeMs) * time.Millisecond)
 	}
+	if err != nil {
+		state.failedInsert = true
+	}
 	return err
 }
 
 // DoTransaction implements the Workload DoTransaction interface.
 func (c *core) DoTransaction(ctx context.Context, db ycsb.DB) error {
+	if err := c.createIndex(ctx, db); err != nil {
+		return err
+	}
 	state := ctx.Value(stateKey).(*coreState)
 	r := state.r
 
@@ -372,6 +782,16 @@ func (c *core) DoTransaction(ctx context.Context, db ycsb.DB) error {
 		return c.doTransactionInsert(ctx, db, state)
 	case scan:
 		return c.doTransactionScan(ctx, db, state)
+	case indexRead:
+		return c.doTransactionIndexRead(ctx, db, state)
+	case indexScan:
+		return c.doTransactionIndexScan(ctx, db, state)
+	case indexCount:
+		return c.doTransactionIndexCount(ctx, db, state)
+	case scanRange:
+		return c.doTransactionScanRange(ctx, db, state)
+	case deleteRange:
+		return c.doTransactionDeleteRange(ctx, db, state)
 	default:
 		return c.doTransactionReadModifyWrite(ctx, db, state)
 	}
@@ -516,6 +936,85 @@ func (c *core) doTransactionScan(ctx context.Context, db ycsb.DB, state *coreSta
 	return err
 }
 
+func (c *core) readFields(state *coreState) []string {
+	if !c.readAllFields {
+		return []string{state.fieldNames[c.fieldChooser.Next(state.r)]}
+	}
+	return state.fieldNames
+}
+
+func (c *core) doTransactionIndexRead(ctx context.Context, db ycsb.DB, state *coreState) error {
+	queryDB, err := getQueryDB(db)
+	if err != nil {
+		return err
+	}
+	keyName := c.buildKeyName(ctx, c.nextKeyNum(state))
+
+	_, err = queryDB.Query(ctx, c.table, indexField, c.buildIndexValue(keyName), c.readFields(state))
+	return err
+}
+
+func (c *core) doTransactionIndexScan(ctx context.Context, db ycsb.DB, state *coreState) error {
+	queryDB, err := getQueryDB(db)
+	if err != nil {
+		return err
+	}
+	keyName := c.buildKeyName(ctx, c.nextKeyNum(state))
+	scanLen := c.scanLength.Next(state.r)
+
+	_, err = queryDB.RangeQuery(ctx, c.table, indexField, c.buildIndexValue(keyName), int(scanLen), c.readFields(state))
+	return err
+}
+
+func (c *core) doTransactionIndexCount(ctx context.Context, db ycsb.DB, state *coreState) error {
+	queryDB, err := getQueryDB(db)
+	if err != nil {
+		return err
+	}
+	start := c.buildIndexValue(c.buildKeyName(ctx, c.nextKeyNum(state)))
+	end := c.buildIndexValue(c.buildKeyName(ctx, c.nextKeyNum(state)))
+	if bytes.Compare(start, end) > 0 {
+		start, end = end, start
+	}
+
+	_, err = queryDB.Count(ctx, c.table, indexField, start, end)
+	return err
+}
+
+// nextKeyRange returns a key range from the next key. The keys are ordered, so the range
+// covers about scanlength records.
+func (c *core) nextKeyRange(ctx context.Context, state *coreState) (string, string, int) {
+	keyNum := c.nextKeyNum(state)
+	length := c.scanLength.Next(state.r)
+	startKey := c.buildKeyName(ctx, keyNum)
+	endKey := c.buildKeyName(ctx, keyNum+length)
+	if startKey > endKey {
+		startKey, endKey = endKey, startKey
+	}
+	return startKey, endKey, int(length)
+}
+
+func (c *core) doTransactionScanRange(ctx context.Context, db ycsb.DB, state *coreState) error {
+	rangeDB, err := getRangeDB(db)
+	if err != nil {
+		return err
+	}
+	startKey, endKey, limit := c.nextKeyRange(ctx, state)
+
+	_, err = rangeDB.ScanRange(ctx, c.table, startKey, endKey, limit, c.readFields(state))
+	return err
+}
+
+func (c *core) doTransactionDeleteRange(ctx context.Context, db ycsb.DB, state *coreState) error {
+	rangeDB, err := getRangeDB(db)
+	if err != nil {
+		return err
+	}
+	startKey, endKey, _ := c.nextKeyRange(ctx, state)
+
+	return rangeDB.DeleteRange(ctx, c.table, startKey, endKey)
+}
+
 func (c *core) doTransactionUpdate(ctx context.Context, db ycsb.DB, state *coreState) error {
 	keyNum := c.nextKeyNum(state)
 	keyName := c.buildKeyName(ctx, keyNum)
@@ -614,40 +1113,43 @@ func (coreCreator) Create(p *properties.Properties) (ycsb.Workload, error) {
 	c := new(core)
 	c.p = p
 	c.table = p.GetString(prop.TableName, prop.TableNameDefault)
-	c.fieldCount = p.GetInt64(prop.FieldCount, prop.FieldCountDefault)
-	c.fieldNames = make([]string, c.fieldCount)
-	for i := int64(0); i < c.fieldCount; i++ {
-		c.fieldNames[i] = fmt.Sprintf("field%d", i)
-	}
-	c.fieldLengthGenerator = getFieldLengthGenerator(p)
+	c.createFields(p)
 	c.recordCount = p.GetInt64(prop.RecordCount, prop.RecordCountDefault)
 	if c.recordCount == 0 {
 		c.recordCount = int64(math.MaxInt32)
 	}
 
-	requestDistrib := p.GetString(prop.RequestDistribution, prop.RequestDistributionDefault)
 	maxScanLength := p.GetInt64(prop.MaxScanLength, prop.MaxScanLengthDefault)
 	scanLengthDistrib := p.GetString(prop.ScanLengthDistribution, prop.ScanLengthDistributionDefault)
 
-	host, err := os.Hostname()
-	if err != nil {
-		fmt.Printf("%s", err)
-	}
-	c.keyDefault = host
-
 	insertStart := p.GetInt64(prop.InsertStart, prop.InsertStartDefault)
-	insertCount := p.GetInt64(prop.InsertCount, c.recordCount-insertStart)
-	if c.recordCount < insertStart+insertCount {
-		util.Fatalf("record count %d must be bigger than insert start %d + count %d",
-			c.recordCount, insertStart, insertCount)
-	}
-	c.zeroPadding = p.GetInt64(prop.ZeroPadding, prop.ZeroPaddingDefault)
 	c.readAllFields = p.GetBool(prop.ReadAllFields, prop.ReadALlFieldsDefault)
 	c.writeAllFields = p.GetBool(prop.WriteAllFields, prop.WriteAllFieldsDefault)
 	c.dataIntegrity = p.GetBool(prop.DataIntegrity, prop.DataIntegrityDefault)
-	fieldLengthDistribution := p.GetString(prop.FieldLengthDistribution, prop.FieldLengthDistributionDefault)
-	if c.dataIntegrity && fieldLengthDistribution != "constant" {
-		util.Fatal("must have constant field size to check data integrity")
+	c.secondaryIndex = p.GetBool(prop.SecondaryIndex, prop.SecondaryIndexDefault)
+	if !c.secondaryIndex && p.GetFloat64(prop.IndexReadProportion, prop.IndexReadProportionDefault)+
+		p.GetFloat64(prop.IndexScanProportion, prop.IndexScanProportionDefault)+
+		p.GetFloat64(prop.IndexCountProportion, prop.IndexCountProportionDefault) > 0 {
+		util.Fatal("must enable secondaryindex to do index operations")
+	}
+	if c.dataIntegrity {
+		for _, f := range c.fields {
+			if f.LengthDistribution != "constant" {
+				util.Fatal("must have constant field size to check data integrity")
+			}
+			if f.Type == util.FieldTypeInt || f.Type == util.FieldTypeTimestamp || f.Presence < 1 {
+				util.Fatalf("field %s must be a bytes or string field with presence 1 to check data integrity", f.Name)
+			}
+		}
+	}
+	if c.secondaryIndex {
+		if i, ok := c.fieldIndices[indexField]; !ok || c.fields[i].Type == util.FieldTypeInt || c.fields[i].Type == util.FieldTypeTimestamp {
+			util.Fatalf("must have the bytes or string field %s to create the secondary index", indexField)
+		}
+	}
+	var err error
+	if c.valueGenerator, err = util.NewValueGenerator(p); err != nil {
+		util.Fatalf("create value generator failed %v", err)
 	}
 
 	if p.GetString(prop.InsertOrder, prop.InsertOrderDefault) == "hashed" {
@@ -655,45 +1157,40 @@ func (coreCreator) Create(p *properties.Properties) (ycsb.Workload, error) {
 	} else {
 		c.orderedInserts = true
 	}
+	c.keyNamespace = keyNamespace(p)
+	keyPrefix := p.GetString(prop.KeyPrefix, prop.KeyPrefixDefault)
+	if len(c.keyNamespace) > 0 {
+		keyPrefix = c.keyNamespace + "_" + keyPrefix
+	}
+	if c.keys, err = newKeyBuilder(p, keyPrefix, c.orderedInserts); err != nil {
+		util.Fatalf("create key builder failed %v", err)
+	}
+	if !c.keys.ordered() && p.GetFloat64(prop.ScanRangeProportion, prop.ScanRangeProportionDefault)+
+		p.GetFloat64(prop.DeleteRangeProportion, prop.DeleteRangeProportionDefault) > 0 {
+		util.Fatal("must use the ordered keys to do range operations, set insertorder=ordered")
+	}
 
+	c.insertStart = insertStart
+	c.insertEnd = insertStart + p.GetInt64(prop.InsertCount, c.recordCount-insertStart)
+	c.appendLoad = p.GetBool(prop.LoadAppend, prop.LoadAppendDefault)
 	c.keySequence = generator.NewCounter(insertStart)
 	c.operationChooser = createOperationGenerator(p)
 
-	c.transactionInsertKeySequence = generator.NewAcknowledgedCounter(c.recordCount)
-	switch requestDistrib {
-	case "uniform":
-		c.keyChooser = generator.NewUniform(insertStart, insertStart+insertCount-1)
-	case "sequential":
-		c.keyChooser = generator.NewSequential(insertStart, insertStart+insertCount-1)
-	case "zipfian":
-		insertProportion := p.GetFloat64(prop.InsertProportion, prop.InsertProportionDefault)
-		opCount := p.GetInt64(prop.OperationCount, 0)
-		expectedNewKeys := int64(float64(opCount) * insertProportion * 2.0)
-		c.keyChooser = generator.NewScrambledZipfian(insertStart, insertStart+insertCount+expectedNewKeys, generator.ZipfianConstant)
-	case "latest":
-		c.keyChooser = generator.NewSkewedLatest(c.transactionInsertKeySequence)
-	case "hotspot":
-		hotsetFraction := p.GetFloat64(prop.HotspotDataFraction, prop.HotspotDataFractionDefault)
-		hotopnFraction := p.GetFloat64(prop.HotspotOpnFraction, prop.HotspotOpnFractionDefault)
-		c.keyChooser = generator.NewHotspot(insertStart, insertStart+insertCount-1, hotsetFraction, hotopnFraction)
-	case "exponential":
-		percentile := p.GetFloat64(prop.ExponentialPercentile, prop.ExponentialPercentileDefault)
-		frac := p.GetFloat64(prop.ExponentialFrac, prop.ExponentialFracDefault)
-		c.keyChooser = generator.NewExponential(percentile, float64(c.recordCount)*frac)
-	default:
-		util.Fatalf("unknown request distribution %s", requestDistrib)
+	c.maxInsertKey = -1
+	if err = c.createKeyChooser(); err != nil {
+		util.Fatal(err)
 	}
 
 	c.fieldChooser = generator.NewUniform(0, c.fieldCount-1)
-	switch scanLengthDistrib {
-	case "uniform":
-		c.scanLength = generator.NewUniform(1, maxScanLength)
-	case "zipfian":
-		c.scanLength = generator.NewZipfianWithRange(1, maxScanLength, generator.ZipfianConstant)
-	default:
-		util.Fatalf("distribution %s not allowed for scan length", scanLengthDistrib)
+	c.scanLength, err = generator.New(scanLengthDistrib, p, generator.Args{
+		Min: 1,
+		Max: maxScanLength,
+	})
+	if err != nil {
+		util.Fatalf("create scan length generator failed %v", err)
 	}
 
+	c.seed = p.GetInt64(prop.RandomSeed, prop.RandomSeedDefault)
 	c.insertionRetryLimit = p.GetInt64(prop.InsertionRetryLimit, prop.InsertionRetryLimitDefault)
 	c.insertionRetryInterval = p.GetInt64(prop.InsertionRetryInterval, prop.InsertionRetryIntervalDefault)
 
@@ -0,0 +1,52 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// plainDB only implements the DB interface of the memory database.
+type plainDB struct {
+	ycsb.DB
+}
+
+func TestOptionalInterfaces(t *testing.T) {
+	memDB, err := ycsb.GetDBCreator("memory").Create(properties.NewProperties())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for s, want := range map[string]string{
+		"secondaryindex=true\nindexreadproportion=1":   "QueryDB",
+		"insertorder=ordered\nscanrangeproportion=1":   "RangeDB",
+		"insertorder=ordered\ndeleterangeproportion=1": "RangeDB",
+	} {
+		p := properties.MustLoadString("recordcount=10\nkeynamespace=none\nreadproportion=0\nupdateproportion=0\n" + s)
+		w, err := coreCreator{}.Create(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := w.InitThread(context.Background(), 0, 1)
+		if err = w.DoTransaction(ctx, plainDB{memDB}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("want the error of no %s for %q, but got %v", want, s, err)
+		}
+		w.CleanupThread(ctx)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
+)
+
+// The key formats.
+const (
+	// prefix + zero-padded number
+	keyFormatDefault = "default"
+	// big-endian number in keylength bytes
+	keyFormatBinary = "binary"
+	// random UUID derived from the number
+	keyFormatUUID4 = "uuid4"
+	// time-ordered UUID, whose timestamp increases with the number
+	keyFormatUUID7 = "uuid7"
+	// prefix + timestamp, which increases with the number
+	keyFormatTimestamp = "timestamp"
+	// tenant<t>/user<u>/item<number>
+	keyFormatPath = "path"
+)
+
+// The key namespace modes.
+const (
+	// no namespace
+	keyNamespaceNone = "none"
+	// the hostname of the client, which keeps the data of the clients apart
+	keyNamespaceHostname = "hostname"
+	// the label property, shared by the clients of the same benchmark
+	keyNamespaceLabel = "label"
+	// the keynamespace.value property
+	keyNamespaceExplicit = "explicit"
+)
+
+// keyNamespace returns the namespace put before the key prefix, chosen by keynamespace.
+func keyNamespace(p *properties.Properties) string {
+	mode := strings.ToLower(p.GetString(prop.KeyNamespace, prop.KeyNamespaceDefault))
+	switch mode {
+	case keyNamespaceNone:
+		return ""
+	case keyNamespaceHostname:
+		host, err := os.Hostname()
+		if err != nil {
+			util.Fatalf("get hostname for the key namespace failed %v", err)
+		}
+		return host
+	case keyNamespaceLabel:
+		label := p.GetString(prop.Label, "")
+		if len(label) == 0 {
+			util.Fatalf("must set %s for keynamespace=%s", prop.Label, mode)
+		}
+		return label
+	case keyNamespaceExplicit:
+		value := p.GetString(prop.KeyNamespaceValue, "")
+		if len(value) == 0 {
+			util.Fatalf("must set %s for keynamespace=%s", prop.KeyNamespaceValue, mode)
+		}
+		return value
+	default:
+		util.Fatalf("unknown key namespace mode %s", mode)
+		return ""
+	}
+}
+
+// keyEpoch is the time of the key number 0 in the uuid7 and timestamp keys, so the
+// same number always builds the same key.
+var keyEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const keyTimestampLayout = "20060102T150405.000000"
+
+// keyBuilder builds the record keys from the key numbers. The same number always
+// builds the same key, so the keys inserted in the load phase can be found in the run phase.
+type keyBuilder struct {
+	format         string
+	prefix         string
+	zeroPadding    int64
+	length         int
+	orderedInserts bool
+	tenants        int64
+	users          int64
+}
+
+func newKeyBuilder(p *properties.Properties, prefix string, orderedInserts bool) (*keyBuilder, error) {
+	k := &keyBuilder{
+		format:         strings.ToLower(p.GetString(prop.KeyFormat, prop.KeyFormatDefault)),
+		prefix:         prefix,
+		zeroPadding:    p.GetInt64(prop.ZeroPadding, prop.ZeroPaddingDefault),
+		length:         p.GetInt(prop.KeyLength, prop.KeyLengthDefault),
+		orderedInserts: orderedInserts,
+		tenants:        p.GetInt64(prop.KeyTenants, prop.KeyTenantsDefault),
+		users:          p.GetInt64(prop.KeyUsers, prop.KeyUsersDefault),
+	}
+
+	switch k.format {
+	case keyFormatDefault, keyFormatUUID4, keyFormatUUID7, keyFormatTimestamp:
+	case keyFormatBinary:
+		if k.length == 0 {
+			k.length = 8
+		}
+	case keyFormatPath:
+		if k.tenants <= 0 || k.users <= 0 {
+			return nil, fmt.Errorf("%s and %s must be positive", prop.KeyTenants, prop.KeyUsers)
+		}
+	default:
+		return nil, fmt.Errorf("unknown key format %s", k.format)
+	}
+	if k.length < 0 {
+		return nil, fmt.Errorf("invalid key length %d", k.length)
+	}
+	if n := k.maxLength(maxKeyNum(p)); k.length > 0 && k.length < n {
+		return nil, fmt.Errorf("keylength %d is shorter than the %s keys of up to %d bytes", k.length, k.format, n)
+	}
+	return k, nil
+}
+
+// maxKeyNum returns the largest key number of the loaded and the inserted records.
+func maxKeyNum(p *properties.Properties) int64 {
+	recordCount := p.GetInt64(prop.RecordCount, prop.RecordCountDefault)
+	if recordCount == 0 {
+		recordCount = int64(math.MaxInt32)
+	}
+	return recordCount + p.GetInt64(prop.OperationCount, 0)
+}
+
+// maxLength returns the length of the longest key up to the number, which must fit in
+// keylength, or the keys would collide.
+func (k *keyBuilder) maxLength(keyNum int64) int {
+	if !k.orderedInserts {
+		keyNum = math.MaxInt64
+	}
+	digits := len(strconv.FormatInt(keyNum, 10))
+	if int64(digits) < k.zeroPadding {
+		digits = int(k.zeroPadding)
+	}
+
+	switch k.format {
+	case keyFormatBinary:
+		if n := (bits.Len64(uint64(keyNum)) + 7) / 8; n > 0 {
+			return n
+		}
+		return 1
+	case keyFormatUUID4, keyFormatUUID7:
+		return 36
+	case keyFormatTimestamp:
+		return len(k.prefix) + len(keyTimestampLayout)
+	case keyFormatPath:
+		return len(fmt.Sprintf("tenant%d/user%d/item", k.tenants-1, k.users-1)) + digits
+	default:
+		return len(k.prefix) + digits
+	}
+}
+
+// ordered returns whether the keys follow the number order, so a key range covers the
+// numbers between its ends.
+func (k *keyBuilder) ordered() bool {
+	switch k.format {
+	case keyFormatUUID7, keyFormatTimestamp:
+		return true
+	case keyFormatDefault, keyFormatBinary:
+		return k.orderedInserts
+	default:
+		return false
+	}
+}
+
+// build builds the key of the number. The uuid7 and timestamp keys always follow the
+// number order, and the other keys are hashed unless the inserts are ordered.
+func (k *keyBuilder) build(keyNum int64) string {
+	if !k.orderedInserts && k.format != keyFormatUUID7 && k.format != keyFormatTimestamp {
+		keyNum = util.Hash64(keyNum)
+	}
+
+	switch k.format {
+	case keyFormatBinary:
+		return k.buildBinary(keyNum)
+	case keyFormatUUID4:
+		return k.fitLength(k.buildUUID4(keyNum))
+	case keyFormatUUID7:
+		return k.fitLength(k.buildUUID7(keyNum))
+	case keyFormatTimestamp:
+		return k.fitLength(k.prefix + keyEpoch.Add(time.Duration(keyNum)*time.Microsecond).Format(keyTimestampLayout))
+	case keyFormatPath:
+		h := mix64(uint64(keyNum))
+		return k.buildNumber(fmt.Sprintf("tenant%d/user%d/item", h%uint64(k.tenants), h/uint64(k.tenants)%uint64(k.users)), keyNum)
+	default:
+		return k.buildNumber(k.prefix, keyNum)
+	}
+}
+
+// buildNumber builds the prefix and the zero-padded number. With keylength, the number
+// is padded to fill the key.
+func (k *keyBuilder) buildNumber(prefix string, keyNum int64) string {
+	key := fmt.Sprintf("%s%0[3]*[2]d", prefix, keyNum, k.zeroPadding)
+	if k.length > len(key) {
+		return fmt.Sprintf("%s%0[3]*[2]d", prefix, keyNum, k.length-len(prefix))
+	}
+	return k.fitLength(key)
+}
+
+// fitLength pads the fixed-width key with '0' at the end to make the key exactly keylength
+// bytes. newKeyBuilder makes sure that no key is longer.
+func (k *keyBuilder) fitLength(key string) string {
+	if k.length <= len(key) {
+		return key
+	}
+	return key + strings.Repeat("0", k.length-len(key))
+}
+
+// mix64 is the splitmix64 function, which is a bijection and spreads the close numbers
+// well, unlike the fnv hash.
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// buildBinary builds the big-endian number, padded with zero bytes in front or cut to
+// the lowest bytes to make keylength bytes. newKeyBuilder makes sure that the cut bytes
+// are zero.
+func (k *keyBuilder) buildBinary(keyNum int64) string {
+	var num [8]byte
+	binary.BigEndian.PutUint64(num[:], uint64(keyNum))
+
+	b := make([]byte, k.length)
+	if k.length >= len(num) {
+		copy(b[k.length-len(num):], num[:])
+	} else {
+		copy(b, num[len(num)-k.length:])
+	}
+	return string(b)
+}
+
+func (k *keyBuilder) buildUUID4(keyNum int64) string {
+	var u [16]byte
+	h := mix64(uint64(keyNum))
+	binary.BigEndian.PutUint64(u[0:8], h)
+	binary.BigEndian.PutUint64(u[8:16], mix64(h))
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u)
+}
+
+func (k *keyBuilder) buildUUID7(keyNum int64) string {
+	var u [16]byte
+	ms := keyEpoch.UnixNano()/int64(time.Millisecond) + keyNum
+	binary.BigEndian.PutUint64(u[0:8], uint64(ms)<<16)
+	binary.BigEndian.PutUint64(u[8:16], mix64(uint64(keyNum)))
+	// The 12 bits after the timestamp come from the hash too.
+	u[6] = 0x70 | u[9]&0x0f
+	u[7] = u[10]
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u)
+}
+
+func formatUUID(u [16]byte) string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
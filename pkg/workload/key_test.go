@@ -0,0 +1,123 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/magiconair/properties"
+)
+
+// uuidPattern matches the UUID of the version.
+const uuidPattern = `[0-9a-f]{8}-[0-9a-f]{4}-%d[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`
+
+func newTestKeyBuilder(s string, orderedInserts bool) (*keyBuilder, error) {
+	p := properties.MustLoadString("recordcount=1000\noperationcount=1000\n" + s)
+	return newKeyBuilder(p, "user", orderedInserts)
+}
+
+func TestKeyFormats(t *testing.T) {
+	for _, c := range []struct {
+		props   string
+		ordered bool
+		pattern string
+		// length is the length of every key, 0 for any length.
+		length int
+		// sorted is whether the keys sort in the number order.
+		sorted bool
+	}{
+		{"", true, `^user\d{1,4}$`, 0, false},
+		{"", false, `^user\d+$`, 0, false},
+		{"keylength=16", true, `^user\d{12}$`, 16, false},
+		{"zeropadding=8", true, `^user\d{8}$`, 12, true},
+		{"keyformat=binary", true, `(?s).*`, 8, true},
+		{"keyformat=binary\nkeylength=2", true, `(?s).*`, 2, true},
+		{"keyformat=binary\nkeylength=12", false, `(?s).*`, 12, false},
+		{"keyformat=uuid4", true, fmt.Sprintf(`^`+uuidPattern+`$`, 4), 36, false},
+		{"keyformat=uuid4\nkeylength=40", false, fmt.Sprintf(`^`+uuidPattern+`0000$`, 4), 40, false},
+		{"keyformat=UUID7", false, fmt.Sprintf(`^`+uuidPattern+`$`, 7), 36, true},
+		{"keyformat=timestamp", false, `^user2020\d{4}T\d{6}\.\d{6}$`, 26, true},
+		{"keyformat=path\nkeyformat.tenants=3\nkeyformat.users=5", false, `^tenant[0-2]/user[0-4]/item\d+$`, 0, false},
+		{"keyformat=path\nkeylength=64", true, `^tenant\d+/user\d+/item\d+$`, 64, false},
+	} {
+		k, err := newTestKeyBuilder(c.props, c.ordered)
+		if err != nil {
+			t.Fatalf("%q: %v", c.props, err)
+		}
+		if c.sorted && !k.ordered() {
+			t.Fatalf("%q: want the ordered keys", c.props)
+		}
+		// The same number always builds the same key, even in another builder.
+		again, err := newTestKeyBuilder(c.props, c.ordered)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pattern := regexp.MustCompile(c.pattern)
+		keys := make(map[string]int64)
+		var last string
+		for i := int64(0); i < 2000; i++ {
+			key := k.build(i)
+			if !pattern.MatchString(key) {
+				t.Fatalf("%q: the key %q of %d doesn't match %s", c.props, key, i, c.pattern)
+			}
+			if c.length > 0 && len(key) != c.length {
+				t.Fatalf("%q: want the key of %d bytes, but got %q", c.props, c.length, key)
+			}
+			if j, ok := keys[key]; ok {
+				t.Fatalf("%q: the numbers %d and %d build the same key %q", c.props, j, i, key)
+			}
+			if key2 := again.build(i); key2 != key {
+				t.Fatalf("%q: the number %d builds %q and %q", c.props, i, key, key2)
+			}
+			if c.sorted && i > 0 && key <= last {
+				t.Fatalf("%q: the key %q of %d doesn't sort after %q", c.props, key, i, last)
+			}
+			keys[key] = i
+			last = key
+		}
+	}
+}
+
+func TestKeyLengthValidation(t *testing.T) {
+	for _, c := range []struct {
+		props   string
+		ordered bool
+	}{
+		// The longest key is user1999.
+		{"keylength=7", true},
+		// The hashed numbers take all the digits.
+		{"keylength=16", false},
+		{"keylength=-1", true},
+		// 1999 needs 2 bytes, and the hashed numbers need 8 bytes.
+		{"keyformat=binary\nkeylength=1", true},
+		{"keyformat=binary\nkeylength=4", false},
+		{"keyformat=uuid4\nkeylength=35", true},
+		{"keyformat=timestamp\nkeylength=25", true},
+		{"keyformat=path\nkeyformat.tenants=0", true},
+		{"keyformat=unknown", true},
+	} {
+		if _, err := newTestKeyBuilder(c.props, c.ordered); err == nil {
+			t.Fatalf("want an error for %q", c.props)
+		}
+	}
+
+	for _, s := range []string{"keylength=8", "keyformat=binary\nkeylength=2", "keyformat=uuid7\nkeylength=36"} {
+		if _, err := newTestKeyBuilder(s, true); err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+	}
+}
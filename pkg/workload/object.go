@@ -0,0 +1,337 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/magiconair/properties"
+	"github.com/pingcap/go-ycsb/pkg/generator"
+	"github.com/pingcap/go-ycsb/pkg/prop"
+	"github.com/pingcap/go-ycsb/pkg/util"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+// properties
+const (
+	// "constant", "uniform", "histogram"
+	objectSizeDistribution   = "object.sizedistribution"
+	objectSize               = "object.size"
+	objectMinSize            = "object.minsize"
+	objectSizeHistogram      = "object.sizehistogram"
+	objectMultipartThreshold = "object.multipartthreshold"
+	objectPartSize           = "object.partsize"
+	objectRangeSize          = "object.rangesize"
+	objectListCount          = "object.listcount"
+	objectListPrefixLength   = "object.listprefixlength"
+
+	objectPutProportion      = "object.putproportion"
+	objectGetProportion      = "object.getproportion"
+	objectRangeGetProportion = "object.rangegetproportion"
+	objectHeadProportion     = "object.headproportion"
+	objectListProportion     = "object.listproportion"
+	objectDeleteProportion   = "object.deleteproportion"
+)
+
+const objectStateKey = contextKey("object")
+
+type objectState struct {
+	r *rand.Rand
+	// buf is the goroutine-local buffer which the object content is sliced from
+	buf []byte
+}
+
+type objectOperationType int64
+
+const (
+	objectPut objectOperationType = iota + 1
+	objectGet
+	objectRangeGet
+	objectHead
+	objectList
+	objectDelete
+)
+
+// object is the object storage benchmark scenario. Every record is a single object whose
+// size follows the configured distribution.
+type object struct {
+	p *properties.Properties
+
+	bucket    string
+	keyPrefix string
+	keys      *keyBuilder
+
+	sizeGenerator      ycsb.Generator
+	valueGenerator     util.ValueGenerator
+	maxSize            int64
+	multipartThreshold int64
+	partSize           int64
+	rangeSize          int64
+	knownSize          int64
+	listCount          int
+	listPrefixLength   int
+
+	orderedInserts               bool
+	keySequence                  ycsb.Generator
+	operationChooser             ycsb.Generator
+	keyChooser                   ycsb.Generator
+	transactionInsertKeySequence *generator.AcknowledgedCounter
+}
+
+func getBytes(p *properties.Properties, name string, defaultValue string) int64 {
+	s := p.GetString(name, defaultValue)
+	n, err := humanize.ParseBytes(s)
+	if err != nil {
+		util.Fatalf("parse %s=%s failed %v", name, s, err)
+	}
+	return int64(n)
+}
+
+func getObjectSizeGenerator(p *properties.Properties) ycsb.Generator {
+	sizeDistribution := p.GetString(objectSizeDistribution, "constant")
+
+	// The histogram block size is the size unit in bytes.
+	sizeGenerator, err := generator.New(sizeDistribution, p, generator.Args{
+		Min:           getBytes(p, objectMinSize, "1KiB"),
+		Max:           getBytes(p, objectSize, "4KiB"),
+		HistogramFile: p.GetString(objectSizeHistogram, "objsize.txt"),
+	})
+	if err != nil {
+		util.Fatalf("create object size generator failed %v", err)
+	}
+
+	return sizeGenerator
+}
+
+func createObjectOperationGenerator(p *properties.Properties) ycsb.Generator {
+	weights := map[int64]float64{
+		int64(objectPut):      p.GetFloat64(objectPutProportion, 0.5),
+		int64(objectGet):      p.GetFloat64(objectGetProportion, 0.5),
+		int64(objectRangeGet): p.GetFloat64(objectRangeGetProportion, 0),
+		int64(objectHead):     p.GetFloat64(objectHeadProportion, 0),
+		int64(objectList):     p.GetFloat64(objectListProportion, 0),
+		int64(objectDelete):   p.GetFloat64(objectDeleteProportion, 0),
+	}
+
+	operationChooser, err := generator.New(p.GetString(prop.OperationDistribution, prop.OperationDistributionDefault), p, generator.Args{
+		Min:     int64(objectPut),
+		Max:     int64(objectDelete),
+		Weights: weights,
+	})
+	if err != nil {
+		util.Fatalf("create operation generator failed %v", err)
+	}
+	return operationChooser
+}
+
+// InitThread implements the Workload InitThread interface.
+func (o *object) InitThread(ctx context.Context, _ int, _ int) context.Context {
+	state := &objectState{
+		r: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	return context.WithValue(ctx, objectStateKey, state)
+}
+
+// CleanupThread implements the Workload CleanupThread interface.
+func (o *object) CleanupThread(_ context.Context) {
+}
+
+// Close implements the Workload Close interface.
+func (o *object) Close() error {
+	return nil
+}
+
+func (o *object) buildKeyName(keyNum int64) string {
+	return o.keys.build(keyNum)
+}
+
+// buildData returns generated content with the next object size. The content of
+// object.size bytes is generated once and sliced for every size, and only generated
+// again for a larger size. The returned slice is only valid until the next call in the
+// same goroutine.
+func (o *object) buildData(state *objectState) []byte {
+	size := int(o.sizeGenerator.Next(state.r))
+	if len(state.buf) < size {
+		n := int(o.maxSize)
+		if n < size {
+			n = size
+		}
+		state.buf = make([]byte, n)
+		o.valueGenerator.Fill(state.r, state.buf)
+	}
+	return state.buf[:size]
+}
+
+func (o *object) nextKeyNum(state *objectState) int64 {
+	keyNum := int64(math.MaxInt64)
+	for keyNum > o.transactionInsertKeySequence.Last() {
+		keyNum = o.keyChooser.Next(state.r)
+	}
+	return keyNum
+}
+
+func (o *object) put(ctx context.Context, db ycsb.ObjectDB, state *objectState, key string) error {
+	data := o.buildData(state)
+	if int64(len(data)) >= o.multipartThreshold {
+		return db.MultipartUpload(ctx, o.bucket, key, data, o.partSize)
+	}
+	return db.PutObject(ctx, o.bucket, key, data)
+}
+
+func getObjectDB(db ycsb.DB) (ycsb.ObjectDB, error) {
+	objectDB, ok := db.(ycsb.ObjectDB)
+	if !ok {
+		return nil, fmt.Errorf("the %T doesn't implement the ObjectDB interface", db)
+	}
+	return objectDB, nil
+}
+
+// DoInsert implements the Workload DoInsert interface.
+func (o *object) DoInsert(ctx context.Context, db ycsb.DB) error {
+	objectDB, err := getObjectDB(db)
+	if err != nil {
+		return err
+	}
+	state := ctx.Value(objectStateKey).(*objectState)
+	keyNum := o.keySequence.Next(state.r)
+	return o.put(ctx, objectDB, state, o.buildKeyName(keyNum))
+}
+
+// DoBatchInsert implements the Workload DoBatchInsert interface.
+func (o *object) DoBatchInsert(ctx context.Context, batchSize int, db ycsb.DB) error {
+	return fmt.Errorf("the object workload doesn't support the batch mode")
+}
+
+// DoTransaction implements the Workload DoTransaction interface.
+func (o *object) DoTransaction(ctx context.Context, db ycsb.DB) error {
+	objectDB, err := getObjectDB(db)
+	if err != nil {
+		return err
+	}
+	state := ctx.Value(objectStateKey).(*objectState)
+
+	operation := objectOperationType(o.operationChooser.Next(state.r))
+	if operation == objectPut {
+		keyNum := o.transactionInsertKeySequence.Next(state.r)
+		defer o.transactionInsertKeySequence.Acknowledge(keyNum)
+		return o.put(ctx, objectDB, state, o.buildKeyName(keyNum))
+	}
+
+	key := o.buildKeyName(o.nextKeyNum(state))
+	switch operation {
+	case objectGet:
+		_, err = objectDB.GetObject(ctx, o.bucket, key, 0, 0)
+	case objectRangeGet:
+		size := o.knownSize
+		if size == 0 {
+			if size, err = objectDB.HeadObject(ctx, o.bucket, key); err != nil {
+				return err
+			}
+		}
+		var offset int64
+		if size > o.rangeSize {
+			offset = state.r.Int63n(size - o.rangeSize)
+		}
+		_, err = objectDB.GetObject(ctx, o.bucket, key, offset, o.rangeSize)
+	case objectHead:
+		_, err = objectDB.HeadObject(ctx, o.bucket, key)
+	case objectList:
+		// List the objects of the same user with the path keys.
+		prefixLength := len(o.keyPrefix) + o.listPrefixLength
+		if o.keys.format == keyFormatPath {
+			prefixLength = strings.LastIndexByte(key, '/') + 1 + o.listPrefixLength
+		}
+		if prefixLength > len(key) {
+			prefixLength = len(key)
+		}
+		_, err = objectDB.ListObjects(ctx, o.bucket, key[:prefixLength], o.listCount)
+	case objectDelete:
+		err = objectDB.DeleteObject(ctx, o.bucket, key)
+	}
+	return err
+}
+
+// DoBatchTransaction implements the Workload DoBatchTransaction interface.
+func (o *object) DoBatchTransaction(ctx context.Context, batchSize int, db ycsb.DB) error {
+	return fmt.Errorf("the object workload doesn't support the batch mode")
+}
+
+type objectCreator struct {
+}
+
+// Create implements the WorkloadCreator Create interface.
+func (objectCreator) Create(p *properties.Properties) (ycsb.Workload, error) {
+	o := new(object)
+	o.p = p
+	o.bucket = p.GetString(prop.TableName, prop.TableNameDefault)
+	o.keyPrefix = p.GetString(prop.KeyPrefix, prop.KeyPrefixDefault)
+	o.orderedInserts = p.GetString(prop.InsertOrder, prop.InsertOrderDefault) != "hashed"
+	var err error
+	if o.keys, err = newKeyBuilder(p, o.keyPrefix, o.orderedInserts); err != nil {
+		util.Fatalf("create key builder failed %v", err)
+	}
+
+	o.sizeGenerator = getObjectSizeGenerator(p)
+	o.maxSize = getBytes(p, objectSize, "4KiB")
+	if o.valueGenerator, err = util.NewValueGenerator(p); err != nil {
+		util.Fatalf("create value generator failed %v", err)
+	}
+	o.multipartThreshold = getBytes(p, objectMultipartThreshold, "64MiB")
+	o.partSize = getBytes(p, objectPartSize, "8MiB")
+	o.rangeSize = getBytes(p, objectRangeSize, "1MiB")
+	// A range GET doesn't need a HEAD to find the size of the constant distribution.
+	if strings.ToLower(p.GetString(objectSizeDistribution, "constant")) == "constant" {
+		o.knownSize = o.maxSize
+	}
+	o.listCount = p.GetInt(objectListCount, 100)
+	o.listPrefixLength = p.GetInt(objectListPrefixLength, 1)
+
+	recordCount := p.GetInt64(prop.RecordCount, prop.RecordCountDefault)
+	if recordCount == 0 {
+		recordCount = int64(math.MaxInt32)
+	}
+	insertStart := p.GetInt64(prop.InsertStart, prop.InsertStartDefault)
+	insertCount := p.GetInt64(prop.InsertCount, recordCount-insertStart)
+	if recordCount < insertStart+insertCount {
+		util.Fatalf("record count %d must be bigger than insert start %d + count %d",
+			recordCount, insertStart, insertCount)
+	}
+
+	o.keySequence = generator.NewCounter(insertStart)
+	o.operationChooser = createObjectOperationGenerator(p)
+	o.transactionInsertKeySequence = generator.NewAcknowledgedCounter(recordCount)
+
+	requestDistrib := p.GetString(prop.RequestDistribution, prop.RequestDistributionDefault)
+	o.keyChooser, err = generator.New(requestDistrib, p, generator.Args{
+		Min:       insertStart,
+		Max:       insertStart + insertCount - 1,
+		Scrambled: true,
+		Basis:     o.transactionInsertKeySequence,
+	})
+	if err != nil {
+		util.Fatalf("create request distribution failed %v", err)
+	}
+
+	return o, nil
+}
+
+func init() {
+	ycsb.RegisterWorkloadCreator("object", objectCreator{})
+}
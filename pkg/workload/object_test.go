@@ -0,0 +1,254 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties"
+	_ "github.com/pingcap/go-ycsb/db/memory"
+	"github.com/pingcap/go-ycsb/pkg/ycsb"
+)
+
+const objectTestWorkload = `
+recordcount=100
+table=bucket
+object.sizedistribution=uniform
+object.minsize=1KiB
+object.size=8KiB
+object.multipartthreshold=6KiB
+object.partsize=2KiB
+object.rangesize=512
+object.listcount=10
+`
+
+// recordDB records the counts and the payload bytes of the object operations on the
+// memory database.
+type recordDB struct {
+	ycsb.DB
+	ycsb.ObjectDB
+
+	t      *testing.T
+	counts map[string]int
+	bytes  map[string]int64
+}
+
+func (db *recordDB) record(op string, bytes int) {
+	db.counts[op]++
+	db.bytes[op] += int64(bytes)
+}
+
+func (db *recordDB) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	db.record("PUT", len(data))
+	return db.ObjectDB.PutObject(ctx, bucket, key, data)
+}
+
+func (db *recordDB) GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error) {
+	data, err := db.ObjectDB.GetObject(ctx, bucket, key, offset, length)
+	if offset > 0 || length > 0 {
+		db.record("GET_RANGE", len(data))
+	} else {
+		db.record("GET", len(data))
+	}
+	return data, err
+}
+
+func (db *recordDB) HeadObject(ctx context.Context, bucket string, key string) (int64, error) {
+	db.record("HEAD", 0)
+	return db.ObjectDB.HeadObject(ctx, bucket, key)
+}
+
+func (db *recordDB) ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error) {
+	keys, err := db.ObjectDB.ListObjects(ctx, bucket, prefix, count)
+	db.record("LIST", 0)
+	if len(keys) > count {
+		db.t.Errorf("want at most %d keys, but got %d", count, len(keys))
+	}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			db.t.Errorf("the listed key %s doesn't have the prefix %s", key, prefix)
+		}
+	}
+	return keys, err
+}
+
+func (db *recordDB) DeleteObject(ctx context.Context, bucket string, key string) error {
+	db.record("DELETE", 0)
+	return db.ObjectDB.DeleteObject(ctx, bucket, key)
+}
+
+func (db *recordDB) MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error {
+	db.record("MULTIPART_PUT", len(data))
+	return db.ObjectDB.MultipartUpload(ctx, bucket, key, data, partSize)
+}
+
+func (db *recordDB) reset() {
+	db.counts = make(map[string]int)
+	db.bytes = make(map[string]int64)
+}
+
+// newObjectTest creates the object workload of the properties, and loads the objects into
+// the memory database.
+func newObjectTest(t *testing.T, s string) (*object, *recordDB, context.Context) {
+	p := properties.MustLoadString(objectTestWorkload + s)
+	w, err := objectCreator{}.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	memDB, err := ycsb.GetDBCreator("memory").Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := &recordDB{DB: memDB, ObjectDB: memDB.(ycsb.ObjectDB), t: t}
+	db.reset()
+
+	o := w.(*object)
+	ctx := o.InitThread(context.Background(), 0, 1)
+	for i := 0; i < 100; i++ {
+		if err = o.DoInsert(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return o, db, ctx
+}
+
+// objectSizes returns the total size of the loaded objects.
+func objectSizes(t *testing.T, o *object, db *recordDB, ctx context.Context) int64 {
+	var total int64
+	for i := int64(0); i < 100; i++ {
+		size, err := db.ObjectDB.HeadObject(ctx, "bucket", o.buildKeyName(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size < 1024 || size > 8192 {
+			t.Fatalf("the size %d of the object %d is out of [1KiB, 8KiB]", size, i)
+		}
+		total += size
+	}
+	return total
+}
+
+func TestObjectLoad(t *testing.T) {
+	o, db, ctx := newObjectTest(t, "")
+
+	// The objects not smaller than the multipart threshold are uploaded in parts.
+	if db.counts["PUT"] == 0 || db.counts["MULTIPART_PUT"] == 0 || db.counts["PUT"]+db.counts["MULTIPART_PUT"] != 100 {
+		t.Fatalf("want 100 PUTs and multipart PUTs, but got %v", db.counts)
+	}
+	if put, size := db.bytes["PUT"]+db.bytes["MULTIPART_PUT"], objectSizes(t, o, db, ctx); put != size {
+		t.Fatalf("want %d bytes put, but got %d", size, put)
+	}
+}
+
+func TestObjectTransaction(t *testing.T) {
+	o, db, ctx := newObjectTest(t, `
+object.putproportion=0.1
+object.getproportion=0.3
+object.rangegetproportion=0.2
+object.headproportion=0.2
+object.listproportion=0.2
+object.deleteproportion=0
+`)
+	db.reset()
+	for i := 0; i < 1000; i++ {
+		if err := o.DoTransaction(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, op := range []string{"PUT", "GET", "GET_RANGE", "HEAD", "LIST"} {
+		if db.counts[op] == 0 {
+			t.Fatalf("want some %s, but got %v", op, db.counts)
+		}
+	}
+	// Every range GET reads object.rangesize bytes after a HEAD of the object size.
+	if want := int64(db.counts["GET_RANGE"] * 512); db.bytes["GET_RANGE"] != want {
+		t.Fatalf("want %d bytes of the range GETs, but got %d", want, db.bytes["GET_RANGE"])
+	}
+	if db.counts["HEAD"] < db.counts["GET_RANGE"] {
+		t.Fatalf("want a HEAD for every range GET, but got %v", db.counts)
+	}
+	if bytes := db.bytes["GET"]; bytes < int64(db.counts["GET"]*1024) || bytes > int64(db.counts["GET"]*8192) {
+		t.Fatalf("the %d bytes of %d GETs are out of the object sizes", bytes, db.counts["GET"])
+	}
+	if db.counts["DELETE"] != 0 {
+		t.Fatalf("want no DELETE, but got %d", db.counts["DELETE"])
+	}
+}
+
+func TestObjectDelete(t *testing.T) {
+	o, db, ctx := newObjectTest(t, `
+object.putproportion=0
+object.getproportion=0
+object.deleteproportion=1
+`)
+	for i := 0; i < 50; i++ {
+		if err := o.DoTransaction(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := db.ObjectDB.ListObjects(ctx, "bucket", "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) >= 100 || len(keys) < 50 {
+		t.Fatalf("want 50 to 99 objects after 50 deletes, but got %d", len(keys))
+	}
+}
+
+func TestObjectKnownSize(t *testing.T) {
+	// The distribution name is case-insensitive like the generator registry.
+	o, db, ctx := newObjectTest(t, `
+object.sizedistribution=Constant
+object.size=2KiB
+object.putproportion=0
+object.getproportion=0
+object.rangegetproportion=1
+`)
+	db.reset()
+	for i := 0; i < 100; i++ {
+		if err := o.DoTransaction(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if db.counts["HEAD"] != 0 || db.counts["GET_RANGE"] != 100 || db.bytes["GET_RANGE"] != 100*512 {
+		t.Fatalf("want 100 range GETs of 512 bytes without HEAD, but got %v %v", db.counts, db.bytes)
+	}
+}
+
+func TestObjectBuildData(t *testing.T) {
+	o, _, ctx := newObjectTest(t, "")
+	state := ctx.Value(objectStateKey).(*objectState)
+
+	// The content of object.size is generated once, and every size is sliced from it.
+	first := o.buildData(state)
+	buf := state.buf
+	if len(buf) != 8192 {
+		t.Fatalf("want a buffer of 8KiB, but got %d bytes", len(buf))
+	}
+	for i := 0; i < 100; i++ {
+		data := o.buildData(state)
+		if len(data) < 1024 || len(data) > 8192 {
+			t.Fatalf("the size %d is out of [1KiB, 8KiB]", len(data))
+		}
+		if &data[0] != &buf[0] || !bytes.Equal(data[:1024], first[:1024]) {
+			t.Fatal("want the content sliced from the buffer")
+		}
+	}
+}
@@ -15,11 +15,20 @@ package ycsb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/magiconair/properties"
 )
 
+// ErrNotSupported is returned, or wrapped, by the operations which the database doesn't
+// support. They are counted apart from the failed operations.
+var ErrNotSupported = errors.New("not supported")
+
+// ErrNotFound is returned, or wrapped, by reading a missing record. The databases may
+// also return no values without an error for it.
+var ErrNotFound = errors.New("not found")
+
 // DBCreator creates a database layer.
 type DBCreator interface {
 	Create(p *properties.Properties) (DB, error)
@@ -102,6 +111,94 @@ type AnalyzeDB interface {
 	Analyze(ctx context.Context, table string) error
 }
 
+// QueryDB is the interface for the DB that can query records by the value of a field
+// through a secondary index.
+type QueryDB interface {
+	// CreateIndex creates a secondary index on the field if it doesn't exist.
+	// table: The name of the table.
+	// field: The field to index.
+	CreateIndex(ctx context.Context, table string, field string) error
+
+	// Query reads the records whose field equals the value.
+	// table: The name of the table.
+	// field: The indexed field.
+	// value: The value to look up.
+	// fields: The list of fields to read, nil|empty for reading all.
+	Query(ctx context.Context, table string, field string, value []byte, fields []string) ([]map[string][]byte, error)
+
+	// RangeQuery reads the records whose field is not less than start, ordered by the field.
+	// table: The name of the table.
+	// field: The indexed field.
+	// start: The smallest value to read.
+	// count: The number of records to read.
+	// fields: The list of fields to read, nil|empty for reading all.
+	RangeQuery(ctx context.Context, table string, field string, start []byte, count int, fields []string) ([]map[string][]byte, error)
+
+	// Count returns the number of records whose field is in [start, end].
+	// table: The name of the table.
+	// field: The indexed field.
+	// start: The smallest value to count.
+	// end: The largest value to count.
+	Count(ctx context.Context, table string, field string, start []byte, end []byte) (int64, error)
+}
+
+// RangeDB is the interface for the DB that can scan and delete records in a key range.
+type RangeDB interface {
+	// ScanRange scans records in the key range [startKey, endKey).
+	// table: The name of the table.
+	// startKey: The first record key to read.
+	// endKey: The record key to stop at, which is not read.
+	// limit: The maximum number of records to read.
+	// fields: The list of fields to read, nil|empty for reading all.
+	ScanRange(ctx context.Context, table string, startKey string, endKey string, limit int, fields []string) ([]map[string][]byte, error)
+
+	// DeleteRange deletes all records in the key range [startKey, endKey).
+	// table: The name of the table.
+	// startKey: The first record key to delete.
+	// endKey: The record key to stop at, which is not deleted.
+	DeleteRange(ctx context.Context, table string, startKey string, endKey string) error
+}
+
+// ObjectDB is the interface for the object storage that supports object level operations.
+// The bucket is the table passed by the workload.
+type ObjectDB interface {
+	// PutObject uploads an object in a single request.
+	// bucket: The name of the bucket.
+	// key: The key of the object.
+	// data: The content of the object.
+	PutObject(ctx context.Context, bucket string, key string, data []byte) error
+
+	// GetObject downloads the object, or only a byte range of it.
+	// bucket: The name of the bucket.
+	// key: The key of the object.
+	// offset: The first byte to read.
+	// length: The number of bytes to read, 0 for reading to the end of the object.
+	GetObject(ctx context.Context, bucket string, key string, offset int64, length int64) ([]byte, error)
+
+	// HeadObject returns the size of the object without reading its content.
+	// bucket: The name of the bucket.
+	// key: The key of the object.
+	HeadObject(ctx context.Context, bucket string, key string) (int64, error)
+
+	// ListObjects lists the keys with the given prefix.
+	// bucket: The name of the bucket.
+	// prefix: The prefix of the keys to list.
+	// count: The maximum number of keys to return.
+	ListObjects(ctx context.Context, bucket string, prefix string, count int) ([]string, error)
+
+	// DeleteObject deletes the object.
+	// bucket: The name of the bucket.
+	// key: The key of the object.
+	DeleteObject(ctx context.Context, bucket string, key string) error
+
+	// MultipartUpload uploads an object in parts.
+	// bucket: The name of the bucket.
+	// key: The key of the object.
+	// data: The content of the object.
+	// partSize: The size of each part, the last part may be smaller.
+	MultipartUpload(ctx context.Context, bucket string, key string, data []byte, partSize int64) error
+}
+
 var dbCreators = map[string]DBCreator{}
 
 // RegisterDBCreator registers a creator for the database
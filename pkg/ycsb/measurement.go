@@ -28,6 +28,9 @@ type MeasurementInfo interface {
 type Measurement interface {
 	// Measure measures the operation latency.
 	Measure(latency time.Duration)
+	// MeasureBytes measures the payload bytes transferred by the operation, the sum of
+	// written values for writes and the returned values for reads and scans.
+	MeasureBytes(bytes int64)
 	// Summary returns the summary of the measurement.
 	Summary() string
 	// Info returns the MeasurementInfo of the measurement.
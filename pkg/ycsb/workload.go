@@ -50,6 +50,36 @@ type Workload interface {
 	DoBatchTransaction(ctx context.Context, batchSize int, db DB) error
 }
 
+// MetadataWorkload is the workload which records the metadata of the loaded data in the
+// database, so the run phase can check that it runs on the same data.
+type MetadataWorkload interface {
+	// MetadataKey returns the key of the metadata record, which keeps the metadata of the
+	// data loaded by different clients apart.
+	MetadataKey() string
+
+	// Metadata returns the metadata of the data, which is recorded when the load finishes.
+	Metadata() map[string]string
+
+	// MergeMetadata merges the metadata recorded by another client into the metadata to
+	// record, so the clients loading parts of the same data don't overwrite each other.
+	MergeMetadata(old map[string]string, m map[string]string) (map[string]string, error)
+
+	// CheckMetadata checks the metadata recorded by the load before running, and returns
+	// an error if the workload can't run on the loaded data.
+	CheckMetadata(m map[string]string) error
+}
+
+// CheckpointWorkload is the workload whose load can be resumed from a checkpoint.
+type CheckpointWorkload interface {
+	// Checkpoint returns the key number before which all the keys are inserted by the load,
+	// and the end of the keys to insert.
+	Checkpoint() (next int64, end int64)
+
+	// Resume makes the load insert the keys from next, which is returned by Checkpoint
+	// of the failed load.
+	Resume(next int64, end int64) error
+}
+
 var workloadCreators = map[string]WorkloadCreator{}
 
 // RegisterWorkloadCreator registers a creator for the workload
@@ -46,8 +46,9 @@ var (
 )
 
 type stat struct {
-	OPS float64
-	P99 float64
+	OPS  float64
+	P99  float64
+	MBps float64
 }
 
 func statFieldFunc(c rune) bool {
@@ -70,6 +71,8 @@ func newStat(line string) (*stat, error) {
 			s.OPS = v
 		case "99th(us)":
 			s.P99 = v
+		case "Throughput(MB/s)":
+			s.MBps = v
 		default:
 		}
 	}
@@ -187,7 +190,7 @@ func reportDBStats(logsPath string, workload string, stats dbStats) error {
 
 	fmt.Fprintf(file, "DB")
 	for _, field := range fields {
-		fmt.Fprintf(file, ",%s OPS,%s P99(us)", field, field)
+		fmt.Fprintf(file, ",%s OPS,%s P99(us),%s MB/s", field, field, field)
 	}
 	fmt.Fprint(file, "\n")
 
@@ -196,9 +199,9 @@ func reportDBStats(logsPath string, workload string, stats dbStats) error {
 		for _, field := range fields {
 			s := stat.summary[field]
 			if s == nil {
-				fmt.Fprintf(file, ",0.0")
+				fmt.Fprintf(file, ",0.0,0.0,0.0")
 			} else {
-				fmt.Fprintf(file, ",%.f,%.f", s.OPS, s.P99)
+				fmt.Fprintf(file, ",%.f,%.f,%.2f", s.OPS, s.P99, s.MBps)
 			}
 		}
 		fmt.Fprintf(file, "\n")
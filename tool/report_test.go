@@ -0,0 +1,59 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+const testLog = `READ   - Takes(s): 1.0, Count: 10, OPS: 10.0, Avg(us): 1, Min(us): 1, Max(us): 1, 99th(us): 1, 99.9th(us): 1, 99.99th(us): 1
+Run finished, takes 10s
+READ   - Takes(s): 10.0, Count: 100, OPS: 10.0, Throughput(MB/s): 1.50, AvgSize(B): 157286, Avg(us): 90, Min(us): 10, Max(us): 900, 99th(us): 800, 99.9th(us): 900, 99.99th(us): 900
+UPDATE - Takes(s): 10.0, Count: 20, OPS: 2.0, Avg(us): 90, Min(us): 10, Max(us): 900, 99th(us): 700, 99.9th(us): 900, 99.99th(us): 900
+`
+
+func TestReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	logPath := path.Join(dir, "memory_workloada.log")
+	if err = ioutil.WriteFile(logPath, []byte(testLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := parseDBStat(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read := s.summary["READ"]; read == nil || read.OPS != 10 || read.P99 != 800 || read.MBps != 1.5 {
+		t.Fatalf("want the READ summary, but got %+v", read)
+	}
+
+	if err = reportDBStats(dir, s.workload, dbStats{s}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path.Join(dir, "report", "workloada_summary.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DB,READ OPS,READ P99(us),READ MB/s,UPDATE OPS,UPDATE P99(us),UPDATE MB/s\nmemory,10,800,1.50,2,700,0.00\n"
+	if string(data) != want {
+		t.Fatalf("want\n%s\nbut got\n%s", want, data)
+	}
+}